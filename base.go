@@ -0,0 +1,248 @@
+package girraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Embed Base in a struct to make pointers to the struct graph nodes, without wrapping them in a Graph:
+//
+//	type Step struct {
+//		girraph.Base[*Step]
+//		Name string
+//	}
+//
+//	step := &Step{Name: "build"}
+//	step.Init(step)
+//
+// Init must be called on each new node before it is used; the other methods panic if it wasn't.  The node is its own
+// meta: GetMeta returns the node, and SetMeta copies the exported fields of the provided node other than Base, as do
+// functions that copy nodes, such as Prune.  A node's JSON includes its ID and children along
+// with its own fields; decode it with BaseFromJSON.
+type Base[T Node[T]] struct {
+	ID        string
+	Children  []T
+	parents   []T
+	self      T
+	cache     nodeCache
+	observers observerList[T]
+}
+
+// Implemented by pointers to structs that embed Base.
+type BaseNode[T Node[T]] interface {
+	MetaNode[T, T]
+	Init(self T, options ...Option) T
+	base() *Base[T]
+}
+
+// Set up the node, generating its ID if it doesn't have one.  Self must be the pointer to the struct embedding b.
+func (b *Base[T]) Init(self T, options ...Option) T {
+	b.self = self
+	if b.ID == "" {
+		b.ID = newID(options)
+	}
+	if b.Children == nil {
+		b.Children = []T{}
+	}
+	if b.parents == nil {
+		b.parents = []T{}
+	}
+	return self
+}
+
+func (b *Base[T]) base() *Base[T] {
+	return b
+}
+
+// Get the node embedding b.
+func (b *Base[T]) node() T {
+	var zero T
+	if any(b.self) == any(zero) {
+		panic("girraph: Base.Init not called")
+	}
+	return b.self
+}
+
+func (b *Base[T]) SetID(id string) T {
+	self := b.node()
+	old := b.ID
+	b.ID = id
+	if old != id {
+		emit(self, Event[T]{
+			Type:  IDChanged,
+			Node:  self,
+			OldID: old,
+			NewID: id,
+		})
+	}
+	return self
+}
+
+func (b *Base[T]) GetID() string {
+	return b.ID
+}
+
+func (b *Base[T]) SetChildren(children []T) T {
+	self := b.node()
+	old := b.Children
+	orphans := orphanChildren(children)
+	for _, child := range b.Children {
+		c := baseOf(child)
+		c.parents = removeNode(c.parents, self)
+	}
+	for _, child := range children {
+		baseOf(child).AddParent(self)
+	}
+	b.Children = children
+	b.invalidate()
+	emit(self, childrenEvents(self, old, children, orphans)...)
+	return self
+}
+
+func (b *Base[T]) GetChildren() []T {
+	return b.Children
+}
+
+func (b *Base[T]) AddChild(child T) T {
+	self := b.node()
+	orphan := len(child.GetParents()) == 0
+	baseOf(child).AddParent(self)
+	b.Children = append(b.Children, child)
+	b.invalidate()
+	emit(self, addChildEvents(self, child, len(b.Children)-1, orphan)...)
+	return self
+}
+
+func (b *Base[T]) AddParent(parent T) T {
+	self := b.node()
+	b.parents = append(b.parents, parent)
+	return self
+}
+
+func (b *Base[T]) SetParents(parents []T) T {
+	self := b.node()
+	b.parents = parents
+	return self
+}
+
+func (b *Base[T]) GetParents() []T {
+	return b.parents
+}
+
+func (b *Base[T]) JSON() ([]byte, error) {
+	return json.Marshal(b.node())
+}
+
+// Get the node itself.
+func (b *Base[T]) GetMeta() T {
+	return b.node()
+}
+
+// Copy the exported fields of meta other than Base into the node.
+func (b *Base[T]) SetMeta(meta T) T {
+	self := b.node()
+	old := b.shallowCopy()
+	copyFields(reflect.ValueOf(self).Elem(), reflect.ValueOf(meta).Elem(), b)
+	b.invalidate()
+	emit(self, Event[T]{
+		Type:    MetaChanged,
+		Node:    self,
+		OldMeta: old,
+		NewMeta: meta,
+	})
+	return self
+}
+
+// Encode the node's own fields for Hash, leaving out its ID and children.
+func (b *Base[T]) CanonicalEncoding() ([]byte, error) {
+	data, err := json.Marshal(b.shallowCopy())
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "ID")
+	delete(fields, "Children")
+	return json.Marshal(fields)
+}
+
+func (b *Base[T]) shallowCopy() T {
+	self := reflect.ValueOf(b.node()).Elem()
+	result := reflect.New(self.Type())
+	copyFields(result.Elem(), self, b)
+	node := result.Interface().(T)
+	copied := baseOf(node)
+	copied.ID = b.ID
+	return copied.Init(node)
+}
+
+// Copy the exported fields other than the embedded Base from src to dst, both structs embedding b's type.  Base is
+// left alone, as it holds the node's links and observers.
+func copyFields[T Node[T]](dst, src reflect.Value, b *Base[T]) {
+	t := dst.Type()
+	skip := baseField(t, b)
+	for i := 0; i < t.NumField(); i++ {
+		if i != skip && t.Field(i).IsExported() {
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+}
+
+func (b *Base[T]) getCache() *nodeCache {
+	return &b.cache
+}
+
+func (b *Base[T]) invalidate() {
+	b.cache = nodeCache{}
+	invalidateParents(b.parents)
+}
+
+func (b *Base[T]) getObservers() *observerList[T] {
+	return &b.observers
+}
+
+func baseOf[T Node[T]](node T) *Base[T] {
+	return any(node).(interface{ base() *Base[T] }).base()
+}
+
+// Get the index of the field embedding b in the struct type.
+func baseField[T Node[T]](t reflect.Type, b *Base[T]) int {
+	baseType := reflect.TypeOf(b).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Anonymous && field.Type == baseType {
+			return i
+		}
+	}
+	panic(fmt.Sprintf("%s does not embed %s", t, baseType))
+}
+
+// Decode nested JSON, as written by JSON, into nodes that embed Base.  A node with multiple parents is written out
+// once per parent, so nodes with the same non-empty ID are decoded into a single node shared between the parents.
+func BaseFromJSON[T BaseNode[T]](input []byte) (T, error) {
+	var root T
+	if err := json.Unmarshal(input, &root); err != nil {
+		return root, err
+	}
+	return linkBase(root, make(map[string]T)), nil
+}
+
+func linkBase[T BaseNode[T]](node T, nodes map[string]T) T {
+	b := node.base()
+	if existing, ok := nodes[b.ID]; ok {
+		return existing
+	}
+	if b.ID != "" {
+		nodes[b.ID] = node
+	}
+	children := b.Children
+	b.self = node
+	b.Children = []T{}
+	b.parents = []T{}
+	for _, child := range children {
+		node.AddChild(linkBase(child, nodes))
+	}
+	return node
+}
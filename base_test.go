@@ -0,0 +1,154 @@
+package girraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type step struct {
+	Base[*step]
+	Name     string
+	Estimate int
+	note     string
+}
+
+func newStep(id, name string) *step {
+	s := &step{
+		Name: name,
+	}
+	s.ID = id
+	return s.Init(s)
+}
+
+// A{B{D}, C{D}}, like getGraphFixture.
+func getBaseFixture() *step {
+	a := newStep("A", "node A")
+	b := newStep("B", "node B")
+	c := newStep("C", "node C")
+	d := newStep("D", "node D")
+	b.AddChild(d)
+	c.AddChild(d)
+	return a.AddChild(b).AddChild(c)
+}
+
+func TestBase(t *testing.T) {
+	root := getBaseFixture()
+	b := root.GetChildren()[0]
+	d := b.GetChildren()[0]
+
+	assert.Equal(t, []*step{b, root.GetChildren()[1]}, d.GetParents())
+	assert.Equal(t, "node B", b.Name, "the node is its own meta")
+	assert.Same(t, b, b.GetMeta())
+	assert.Empty(t, Validate(root))
+	assert.Equal(t, "node A\n├── node B\n│   └── node D\n└── node C\n    └── node D (see above)\n", Render(root, RenderOptions[*step]{
+		Label: func(meta *step) string {
+			return meta.Name
+		},
+	}))
+
+	b.SetChildren([]*step{})
+	assert.Equal(t, []*step{root.GetChildren()[1]}, d.GetParents())
+
+	generated := new(step)
+	generated.Init(generated, WithIDGenerator(NewSequentialGenerator("step-")))
+	assert.Equal(t, "step-1", generated.GetID())
+}
+
+func TestBase_SetMeta(t *testing.T) {
+	root := getBaseFixture()
+	calls, _ := recordEvents(t, root)
+	b := root.GetChildren()[0]
+
+	b.note = "kept"
+	b.SetMeta(&step{
+		Name:     "renamed",
+		Estimate: 3,
+		note:     "not copied",
+	})
+	assert.Equal(t, "renamed", b.Name)
+	assert.Equal(t, 3, b.Estimate)
+	assert.Equal(t, "kept", b.note, "unexported fields are not copied")
+	assert.Equal(t, "B", b.GetID(), "the ID and links are kept")
+	assert.Len(t, b.GetChildren(), 1)
+	assert.Equal(t, []*step{root}, b.GetParents())
+	require.Len(t, *calls, 1)
+	assert.Equal(t, MetaChanged, (*calls)[0][0].Type)
+	assert.Empty(t, (*calls)[0][0].Parent)
+
+	journal, err := NewJournal(root, JournalOptions[*step, *step]{})
+	require.Nil(t, err)
+	b.SetMeta(&step{Name: "again"})
+	require.Nil(t, journal.Undo())
+	assert.Equal(t, "renamed", b.Name)
+}
+
+func TestBase_JSON(t *testing.T) {
+	root := getBaseFixture()
+	data, err := root.JSON()
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"ID":"A","Children":[
+		{"ID":"B","Children":[{"ID":"D","Children":[],"Name":"node D","Estimate":0}],"Name":"node B","Estimate":0},
+		{"ID":"C","Children":[{"ID":"D","Children":[],"Name":"node D","Estimate":0}],"Name":"node C","Estimate":0}
+	],"Name":"node A","Estimate":0}`, string(data))
+
+	decoded, err := BaseFromJSON[*step](data)
+	require.Nil(t, err)
+	assert.Empty(t, Validate(decoded))
+	assert.True(t, Equal(root, decoded, EqualOptions[*step]{
+		Meta: func(a, b *step) bool {
+			return a.Name == b.Name
+		},
+	}))
+	d := decoded.GetChildren()[1].GetChildren()[0]
+	assert.Len(t, d.GetParents(), 2, "shared nodes are decoded once")
+}
+
+func TestBase_Copy(t *testing.T) {
+	root := getBaseFixture()
+	hash, err := Hash(root)
+	require.Nil(t, err)
+	renamed := getBaseFixture()
+	renamed.SetID("other")
+	renamedHash, err := Hash(renamed)
+	require.Nil(t, err)
+	assert.Equal(t, hash, renamedHash, "IDs are not hashed")
+
+	filtered, err := Prune(root, func(node *step) bool {
+		return node.Name == "node C"
+	})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"A", "B", "D"}, getIDs(filtered))
+	assert.Equal(t, "node B", filtered.GetChildren()[0].Name)
+	assert.NotSame(t, root.GetChildren()[0], filtered.GetChildren()[0])
+}
+
+func TestBase_NotInitialized(t *testing.T) {
+	s := &step{Name: "not initialized"}
+	assert.PanicsWithValue(t, "girraph: Base.Init not called", func() {
+		s.AddChild(newStep("B", "node B"))
+	})
+	assert.PanicsWithValue(t, "girraph: Base.Init not called", func() {
+		s.SetMeta(&step{Name: "renamed"})
+	})
+	assert.Equal(t, "not initialized", s.Name)
+}
+
+func TestBase_ObserveWhileSettingMeta(t *testing.T) {
+	// SetMeta doesn't touch the observers, so observing at the same time doesn't race.
+	b := getBaseFixture().GetChildren()[0]
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			cancel, err := Observe(b, func(events []Event[*step]) {})
+			assert.Nil(t, err)
+			cancel()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		b.SetMeta(&step{Name: "renamed"})
+	}
+	<-done
+}
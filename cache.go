@@ -0,0 +1,42 @@
+package girraph
+
+// Values computed from a node and its descendants.  Mutating a node clears its cache and the caches of its ancestors,
+// so a node with a cached value always has cached values for its descendants too.
+type nodeCache struct {
+	digest    Digest
+	hasDigest bool
+	height    int
+	hasHeight bool
+	size      int
+	hasSize   bool
+}
+
+func (c *nodeCache) empty() bool {
+	return !c.hasDigest && !c.hasHeight && !c.hasSize
+}
+
+type cacheable interface {
+	getCache() *nodeCache
+	invalidate()
+}
+
+// Clear the values cached for a node and its ancestors.  Mutations made through the node methods do this
+// automatically; call it after changing meta in place, e.g. through a pointer returned by GetMeta.
+func Invalidate[T Node[T]](node T) {
+	if c, ok := any(node).(cacheable); ok {
+		c.invalidate()
+		return
+	}
+	invalidateParents(node.GetParents())
+}
+
+func invalidateParents[T Node[T]](parents []T) {
+	for _, parent := range parents {
+		c, ok := any(parent).(cacheable)
+		if !ok {
+			invalidateParents(parent.GetParents())
+		} else if !c.getCache().empty() {
+			c.invalidate()
+		}
+	}
+}
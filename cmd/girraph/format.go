@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/68696c6c/girraph"
+)
+
+// Meta is kept as raw JSON, so any graph file can be read without knowing its meta type.
+type node = girraph.Graph[json.RawMessage]
+
+const (
+	formatJSON       = "json"
+	formatYAML       = "yaml"
+	formatNormalized = "normalized"
+)
+
+// A graph written as a flat list of nodes, with children referred to by ID.  Unlike the nested JSON, shared nodes are
+// written once and cycles can be represented.
+type normalizedGraph struct {
+	Root  string
+	Nodes []normalizedNode
+}
+
+type normalizedNode struct {
+	ID       string
+	Meta     json.RawMessage `json:",omitempty"`
+	Children []string        `json:",omitempty"`
+}
+
+// The nested format as YAML.
+type yamlNode struct {
+	ID       string      `yaml:"ID"`
+	Meta     any         `yaml:"Meta,omitempty"`
+	Children []*yamlNode `yaml:"Children,omitempty"`
+}
+
+// Read a graph from a file, or stdin if path is "-".  Unless a format is provided, files with a YAML extension and
+// contents that aren't valid JSON are read as YAML, except for contents that start like JSON, which report the JSON
+// error.  Nested and normalized JSON are told apart by their contents.
+func readGraph(path, format string, stdin io.Reader) (node, error) {
+	if format != "" && format != formatJSON && format != formatYAML {
+		return nil, fmt.Errorf("unknown input format %q", format)
+	}
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml":
+			format = formatYAML
+		default:
+			if !json.Valid(data) && !looksLikeJSON(data) {
+				format = formatYAML
+			}
+		}
+	}
+	if format == formatYAML {
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+	result, err := parseGraph(data)
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return nil, fmt.Errorf("failed to read %s at offset %d: %w", path, syntaxErr.Offset, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return result, nil
+}
+
+func looksLikeJSON(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && (data[0] == '{' || data[0] == '[')
+}
+
+func yamlToJSON(data []byte) ([]byte, error) {
+	var value any
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// Parse nested or normalized JSON.
+func parseGraph(data []byte) (node, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	_, hasRoot := fields["Root"]
+	_, hasNodes := fields["Nodes"]
+	if hasRoot && hasNodes {
+		var normalized normalizedGraph
+		if err := json.Unmarshal(data, &normalized); err != nil {
+			return nil, err
+		}
+		return fromNormalized(normalized)
+	}
+	return girraph.GraphFromJSON[json.RawMessage](data)
+}
+
+func fromNormalized(normalized normalizedGraph) (node, error) {
+	nodes := make(map[string]node)
+	for _, n := range normalized.Nodes {
+		if _, ok := nodes[n.ID]; ok {
+			return nil, fmt.Errorf("node %q is listed more than once", n.ID)
+		}
+		nodes[n.ID] = girraph.MakeGraph[json.RawMessage]().SetID(n.ID).SetMeta(n.Meta)
+	}
+	for _, n := range normalized.Nodes {
+		for _, childID := range n.Children {
+			child, ok := nodes[childID]
+			if !ok {
+				return nil, fmt.Errorf("child %q of node %q is not listed", childID, n.ID)
+			}
+			nodes[n.ID].AddChild(child)
+		}
+	}
+	root, ok := nodes[normalized.Root]
+	if !ok {
+		return nil, fmt.Errorf("root %q is not listed", normalized.Root)
+	}
+	return root, nil
+}
+
+func toNormalized(root node) (normalizedGraph, error) {
+	result := normalizedGraph{
+		Root: root.GetID(),
+	}
+	seen := make(map[string]node)
+	var err error
+	girraph.WalkOnce[node](root, girraph.VisitorFuncs[node]{
+		OnEnter: func(n node, depth int) girraph.VisitAction {
+			if existing, ok := seen[n.GetID()]; ok && existing != n {
+				err = fmt.Errorf("node ID %q is not unique", n.GetID())
+				return girraph.Stop
+			}
+			seen[n.GetID()] = n
+			normalized := normalizedNode{
+				ID:   n.GetID(),
+				Meta: n.GetMeta(),
+			}
+			for _, child := range n.GetChildren() {
+				normalized.Children = append(normalized.Children, child.GetID())
+			}
+			result.Nodes = append(result.Nodes, normalized)
+			return girraph.Continue
+		},
+	})
+	return result, err
+}
+
+func toYAML(n node) (*yamlNode, error) {
+	result := &yamlNode{
+		ID: n.GetID(),
+	}
+	if len(n.GetMeta()) > 0 {
+		if err := json.Unmarshal(n.GetMeta(), &result.Meta); err != nil {
+			return nil, fmt.Errorf("node %q: %w", n.GetID(), err)
+		}
+	}
+	for _, child := range n.GetChildren() {
+		converted, err := toYAML(child)
+		if err != nil {
+			return nil, err
+		}
+		result.Children = append(result.Children, converted)
+	}
+	return result, nil
+}
+
+var errCyclic = errors.New("graph contains a cycle; use the normalized format")
+
+func writeGraph(w io.Writer, root node, format string) error {
+	if format != formatNormalized && len(girraph.FindCycles(root)) > 0 {
+		return errCyclic
+	}
+	switch format {
+	case formatJSON:
+		data, err := json.Marshal(root)
+		if err != nil {
+			return err
+		}
+		return writeIndentedJSON(w, data)
+	case formatYAML:
+		converted, err := toYAML(root)
+		if err != nil {
+			return err
+		}
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(converted); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case formatNormalized:
+		normalized, err := toNormalized(root)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(normalized)
+		if err != nil {
+			return err
+		}
+		return writeIndentedJSON(w, data)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func writeIndentedJSON(w io.Writer, data []byte) error {
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// Get the name of a node from the "name" field of its meta, ignoring case, for labels and selectors.
+func metaName(meta json.RawMessage) string {
+	var fields map[string]any
+	if err := json.Unmarshal(meta, &fields); err != nil {
+		return ""
+	}
+	var keys []string
+	for key := range fields {
+		if strings.EqualFold(key, "name") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if name, ok := fields[key].(string); ok {
+			return name
+		}
+	}
+	return ""
+}
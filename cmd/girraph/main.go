@@ -0,0 +1,306 @@
+// Command girraph inspects and converts graph files.
+//
+// Graphs are read from JSON or YAML, either nested like Graph.JSON or in the normalized format, which lists each node
+// once with the IDs of its children.  Meta is kept as is; a "name" field in it is used for labels and selectors.
+//
+// Usage:
+//
+//	girraph validate FILE
+//	girraph stats FILE
+//	girraph render [-style ascii|dot|mermaid] [-depth N] [-plain] FILE
+//	girraph convert [-to json|yaml|normalized] FILE
+//	girraph query [-id ID] [-json] FILE [SELECTOR]
+//	girraph diff [-ignore-order] [-ignore-ids] FILE FILE
+//
+// FILE may be "-" to read from stdin.  Every command accepts -from json|yaml to set the input format.  The exit status
+// is 1 when validate finds problems or diff finds a difference, and 2 for any other error.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/68696c6c/girraph"
+)
+
+var errUsage = errors.New("usage")
+
+// Reported by commands that ran successfully but found a problem, e.g. a difference between graphs.
+var errFound = errors.New("found")
+
+type command struct {
+	usage string
+	run   func(c *context, flags *flag.FlagSet, args []string) error
+}
+
+type context struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	from   *string
+}
+
+func (c *context) read(path string) (node, error) {
+	return readGraph(path, *c.from, c.stdin)
+}
+
+var commands = map[string]command{}
+
+func init() {
+	commands["validate"] = command{
+		usage: "validate FILE",
+		run:   runValidate,
+	}
+	commands["stats"] = command{
+		usage: "stats FILE",
+		run:   runStats,
+	}
+	commands["render"] = command{
+		usage: "render [-style ascii|dot|mermaid] [-depth N] [-plain] FILE",
+		run:   runRender,
+	}
+	commands["convert"] = command{
+		usage: "convert [-to json|yaml|normalized] FILE",
+		run:   runConvert,
+	}
+	commands["query"] = command{
+		usage: "query [-id ID] [-json] FILE [SELECTOR]",
+		run:   runQuery,
+	}
+	commands["diff"] = command{
+		usage: "diff [-ignore-order] [-ignore-ids] FILE FILE",
+		run:   runDiff,
+	}
+	girraph.RegisterNameAccessor(metaName)
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "girraph: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: girraph %s\n", cmd.usage)
+		flags.PrintDefaults()
+	}
+	c := &context{
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		from:   flags.String("from", "", "input format: json or yaml; detected from the file name and contents by default"),
+	}
+	err := cmd.run(c, flags, args[1:])
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errFound):
+		return 1
+	case errors.Is(err, flag.ErrHelp):
+		return 2
+	case errors.Is(err, errUsage):
+		flags.Usage()
+		return 2
+	default:
+		fmt.Fprintf(stderr, "girraph %s: %s\n", args[0], err)
+		return 2
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage:")
+	for _, name := range []string{"validate", "stats", "render", "convert", "query", "diff"} {
+		fmt.Fprintf(w, "  girraph %s\n", commands[name].usage)
+	}
+}
+
+// Parse the flags and check the number of remaining arguments.
+func parseArgs(flags *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() < min || flags.NArg() > max {
+		return nil, errUsage
+	}
+	return flags.Args(), nil
+}
+
+func runValidate(c *context, flags *flag.FlagSet, args []string) error {
+	args, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	root, err := c.read(args[0])
+	if err != nil {
+		return err
+	}
+	problems := girraph.Validate(root)
+	for _, problem := range problems {
+		// Cycles are reported below, with the whole cycle rather than the edge that closes it.
+		if problem.Type != girraph.ProblemCycle {
+			fmt.Fprintln(c.stdout, problem.Error())
+		}
+	}
+	for _, cycle := range girraph.FindCycles(root) {
+		fmt.Fprintf(c.stdout, "cycle: %s\n", cycle)
+	}
+	if len(problems) > 0 {
+		return errFound
+	}
+	fmt.Fprintln(c.stdout, "ok")
+	return nil
+}
+
+func runStats(c *context, flags *flag.FlagSet, args []string) error {
+	args, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	root, err := c.read(args[0])
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(girraph.Stats(root))
+	if err != nil {
+		return err
+	}
+	return writeIndentedJSON(c.stdout, data)
+}
+
+func runRender(c *context, flags *flag.FlagSet, args []string) error {
+	style := flags.String("style", renderASCII, "ascii, dot or mermaid")
+	depth := flags.Int("depth", 0, "the number of levels to render for the ascii style; 0 renders every level")
+	plain := flags.Bool("plain", false, "draw the ascii style without box-drawing characters")
+	args, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	root, err := c.read(args[0])
+	if err != nil {
+		return err
+	}
+	return render(c.stdout, root, *style, *depth, *plain)
+}
+
+func runConvert(c *context, flags *flag.FlagSet, args []string) error {
+	to := flags.String("to", formatNormalized, "json, yaml or normalized")
+	args, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	root, err := c.read(args[0])
+	if err != nil {
+		return err
+	}
+	return writeGraph(c.stdout, root, *to)
+}
+
+func runQuery(c *context, flags *flag.FlagSet, args []string) error {
+	id := flags.String("id", "", "find the nodes with this ID instead of using a selector")
+	asJSON := flags.Bool("json", false, "print the subtree below each match as JSON instead of its ID")
+	args, err := parseArgs(flags, args, 1, 2)
+	if err != nil {
+		return err
+	}
+	if (*id == "") == (len(args) == 1) {
+		return errUsage
+	}
+	root, err := c.read(args[0])
+	if err != nil {
+		return err
+	}
+
+	var matches []node
+	if *id != "" {
+		girraph.WalkOnce[node](root, girraph.VisitorFuncs[node]{
+			OnEnter: func(n node, depth int) girraph.VisitAction {
+				if n.GetID() == *id {
+					matches = append(matches, n)
+				}
+				return girraph.Continue
+			},
+		})
+	} else {
+		result, err := girraph.Select(root, args[1])
+		if err != nil {
+			var selectorErr *girraph.SelectorError
+			if errors.As(err, &selectorErr) {
+				return fmt.Errorf("%w\n%s", err, selectorErr.Context())
+			}
+			return err
+		}
+		matches = result.GetNodes()
+	}
+
+	for _, match := range matches {
+		if !*asJSON {
+			fmt.Fprintln(c.stdout, match.GetID())
+			continue
+		}
+		if err := writeGraph(c.stdout, match, formatJSON); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runDiff(c *context, flags *flag.FlagSet, args []string) error {
+	ignoreOrder := flags.Bool("ignore-order", false, "match children regardless of their order")
+	ignoreIDs := flags.Bool("ignore-ids", false, "compare structure and meta only")
+	args, err := parseArgs(flags, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	a, err := c.read(args[0])
+	if err != nil {
+		return err
+	}
+	b, err := c.read(args[1])
+	if err != nil {
+		return err
+	}
+	mismatch := girraph.Compare(a, b, girraph.EqualOptions[json.RawMessage]{
+		IgnoreChildOrder: *ignoreOrder,
+		IgnoreIDs:        *ignoreIDs,
+		Meta:             equalJSON,
+	})
+	if mismatch != nil {
+		fmt.Fprintln(c.stdout, mismatch.Error())
+		return errFound
+	}
+	fmt.Fprintln(c.stdout, "no differences")
+	return nil
+}
+
+// Compare meta by value, so that formatting and field order don't matter.
+func equalJSON(a, b json.RawMessage) bool {
+	var av, bv any
+	if len(a) > 0 {
+		if err := json.Unmarshal(a, &av); err != nil {
+			return false
+		}
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &bv); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(av, bv)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func runCommand(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestValidate(t *testing.T) {
+	code, out, _ := runCommand("", "validate", "testdata/graph.json")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "ok\n", out)
+
+	code, out, _ = runCommand("", "validate", "testdata/cycle.json")
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "cycle: A -> B -> A\n", out)
+}
+
+func TestStats(t *testing.T) {
+	code, out, _ := runCommand("", "stats", "testdata/graph.json")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, `"Nodes": 4,`)
+	assert.Contains(t, out, `"SharedNodes": 1,`)
+}
+
+func TestRender(t *testing.T) {
+	code, out, _ := runCommand("", "render", "-plain", "testdata/graph.json")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "root\n|-- build\n|   `-- deploy\n`-- check\n    `-- deploy (see above)\n", out)
+
+	code, out, _ = runCommand("", "render", "-style", "dot", "testdata/graph.json")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, `digraph {
+  "A" [label="root"];
+  "B" [label="build"];
+  "D" [label="deploy"];
+  "C" [label="check"];
+  "A" -> "B";
+  "A" -> "C";
+  "B" -> "D";
+  "C" -> "D";
+}
+`, out)
+
+	code, out, _ = runCommand("", "render", "-style", "mermaid", "testdata/cycle.json")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "graph TD\n  n0[\"A\"]\n  n1[\"B\"]\n  n0 --> n1\n  n1 --> n0\n", out)
+}
+
+func TestConvert(t *testing.T) {
+	code, normalized, _ := runCommand("", "convert", "testdata/reordered.yaml")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, normalized, `"Root": "A"`)
+	assert.Equal(t, 1, strings.Count(normalized, `"ID": "D"`), "shared nodes are listed once")
+
+	// Round trip through YAML and back to normalized JSON on stdin.
+	code, yamlOut, _ := runCommand(normalized, "convert", "-to", "yaml", "-")
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.HasPrefix(yamlOut, "ID: A\nMeta:\n  name: root\n  type: task\nChildren:\n"))
+	code, out, _ := runCommand(yamlOut, "convert", "-from", "yaml", "-")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, normalized, out)
+
+	// YAML on stdin is detected without -from.
+	code, out, _ = runCommand(yamlOut, "convert", "-")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, normalized, out)
+
+	code, _, errOut := runCommand("", "convert", "-to", "json", "testdata/cycle.json")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, errOut, "use the normalized format")
+}
+
+func TestQuery(t *testing.T) {
+	code, out, _ := runCommand("", "query", "testdata/graph.json", "//[type=task]/deploy")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "D\n", out)
+
+	code, out, _ = runCommand("", "query", "-id", "B", "-json", "testdata/graph.json")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, `"name": "deploy"`)
+
+	code, _, errOut := runCommand("", "query", "testdata/graph.json", "/A[type")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, errOut, "/A[type\n       ^")
+}
+
+func TestDiff(t *testing.T) {
+	code, out, _ := runCommand("", "diff", "testdata/graph.json", "testdata/reordered.yaml")
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "/A/B and /A/C: IDs differ\n", out)
+
+	code, out, _ = runCommand("", "diff", "-ignore-order", "testdata/graph.json", "testdata/reordered.yaml")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "no differences\n", out)
+}
+
+func TestUsage(t *testing.T) {
+	code, _, errOut := runCommand("", "unknown")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, errOut, "girraph diff [-ignore-order] [-ignore-ids] FILE FILE")
+
+	code, _, errOut = runCommand("", "diff", "testdata/graph.json")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, errOut, "usage: girraph diff")
+}
+
+func TestReadGraph_Errors(t *testing.T) {
+	// JSON with a typo reports the JSON error instead of failing as YAML.
+	code, _, errOut := runCommand(`{"ID": "A", "Children": [}`, "validate", "-")
+	assert.Equal(t, 2, code)
+	assert.Equal(t, "girraph validate: failed to read - at offset 26: invalid character '}' looking for beginning of value\n", errOut)
+
+	code, _, errOut = runCommand("", "validate", "-from", "xml", "testdata/graph.json")
+	assert.Equal(t, 2, code)
+	assert.Equal(t, "girraph validate: unknown input format \"xml\"\n", errOut)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/68696c6c/girraph"
+)
+
+const (
+	renderASCII   = "ascii"
+	renderDOT     = "dot"
+	renderMermaid = "mermaid"
+)
+
+func label(n node) string {
+	if name := metaName(n.GetMeta()); name != "" {
+		return name
+	}
+	return n.GetID()
+}
+
+// Get the nodes below root in depth-first order, each once, along with their edges in the same order.
+func nodesAndEdges(root node) ([]node, []girraph.EdgeKey) {
+	var nodes []node
+	var edges []girraph.EdgeKey
+	girraph.WalkOnce[node](root, girraph.VisitorFuncs[node]{
+		OnEnter: func(n node, depth int) girraph.VisitAction {
+			nodes = append(nodes, n)
+			return girraph.Continue
+		},
+	})
+	for _, n := range nodes {
+		for _, child := range n.GetChildren() {
+			edges = append(edges, girraph.EdgeKey{
+				Parent: n.GetID(),
+				Child:  child.GetID(),
+			})
+		}
+	}
+	return nodes, edges
+}
+
+func render(w io.Writer, root node, style string, depth int, plain bool) error {
+	switch style {
+	case renderASCII:
+		_, err := io.WriteString(w, girraph.Render(root, girraph.RenderOptions[json.RawMessage]{
+			Label: func(meta json.RawMessage) string {
+				return metaName(meta)
+			},
+			MaxDepth: depth,
+			ASCII:    plain,
+		}))
+		return err
+	case renderDOT:
+		return renderDOTGraph(w, root)
+	case renderMermaid:
+		return renderMermaidGraph(w, root)
+	default:
+		return fmt.Errorf("unknown render style %q", style)
+	}
+}
+
+func renderDOTGraph(w io.Writer, root node) error {
+	nodes, edges := nodesAndEdges(root)
+	var out strings.Builder
+	out.WriteString("digraph {\n")
+	for _, n := range nodes {
+		fmt.Fprintf(&out, "  %s [label=%s];\n", strconv.Quote(n.GetID()), strconv.Quote(label(n)))
+	}
+	for _, edge := range edges {
+		fmt.Fprintf(&out, "  %s -> %s;\n", strconv.Quote(edge.Parent), strconv.Quote(edge.Child))
+	}
+	out.WriteString("}\n")
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// Mermaid node IDs are restricted, so nodes are numbered and the IDs are only used for labels.
+func renderMermaidGraph(w io.Writer, root node) error {
+	nodes, _ := nodesAndEdges(root)
+	numbers := make(map[node]int)
+	for i, n := range nodes {
+		numbers[n] = i
+	}
+	var out strings.Builder
+	out.WriteString("graph TD\n")
+	for i, n := range nodes {
+		text := strings.ReplaceAll(label(n), `"`, "#quot;")
+		fmt.Fprintf(&out, "  n%d[\"%s\"]\n", i, text)
+	}
+	for _, n := range nodes {
+		for _, child := range n.GetChildren() {
+			fmt.Fprintf(&out, "  n%d --> n%d\n", numbers[n], numbers[child])
+		}
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
@@ -0,0 +1,159 @@
+package girraph
+
+import "strings"
+
+// Get the weakly connected components containing the provided nodes: the groups of nodes connected by edges in
+// either direction, following both child and parent links.  Components and the nodes in them are in the order they
+// are found, starting from the provided nodes in order.
+func ConnectedComponents[T any](nodes ...Graph[T]) [][]Graph[T] {
+	visited := make(map[Graph[T]]bool)
+	var result [][]Graph[T]
+	for _, start := range nodes {
+		if visited[start] {
+			continue
+		}
+		visited[start] = true
+		component := []Graph[T]{start}
+		for i := 0; i < len(component); i++ {
+			node := component[i]
+			neighbours := append(append([]Graph[T]{}, node.GetChildren()...), node.GetParents()...)
+			for _, neighbour := range neighbours {
+				if !visited[neighbour] {
+					visited[neighbour] = true
+					component = append(component, neighbour)
+				}
+			}
+		}
+		result = append(result, component)
+	}
+	return result
+}
+
+// Get the strongly connected components of the graphs containing the provided nodes, using Tarjan's algorithm: the
+// groups of nodes that can all reach each other through child edges.  Every node in the weakly connected components
+// of the provided nodes is in exactly one component, so a node that is not part of a cycle is a component on its own.
+// A component comes before the components that reach it, so the result is in reverse topological order.
+func StronglyConnectedComponents[T any](nodes ...Graph[T]) [][]Graph[T] {
+	s := &tarjan[T]{
+		index:   make(map[Graph[T]]int),
+		low:     make(map[Graph[T]]int),
+		onStack: make(map[Graph[T]]bool),
+	}
+	for _, component := range ConnectedComponents(nodes...) {
+		for _, node := range component {
+			if _, ok := s.index[node]; !ok {
+				s.connect(node)
+			}
+		}
+	}
+	return s.components
+}
+
+type tarjan[T any] struct {
+	index      map[Graph[T]]int
+	low        map[Graph[T]]int
+	onStack    map[Graph[T]]bool
+	stack      []Graph[T]
+	components [][]Graph[T]
+}
+
+func (s *tarjan[T]) connect(node Graph[T]) {
+	s.index[node] = len(s.index)
+	s.low[node] = s.index[node]
+	s.stack = append(s.stack, node)
+	s.onStack[node] = true
+
+	for _, child := range node.GetChildren() {
+		if _, ok := s.index[child]; !ok {
+			s.connect(child)
+			if s.low[child] < s.low[node] {
+				s.low[node] = s.low[child]
+			}
+		} else if s.onStack[child] && s.index[child] < s.low[node] {
+			s.low[node] = s.index[child]
+		}
+	}
+
+	if s.low[node] != s.index[node] {
+		return
+	}
+	var component []Graph[T]
+	for {
+		top := s.stack[len(s.stack)-1]
+		s.stack = s.stack[:len(s.stack)-1]
+		s.onStack[top] = false
+		component = append(component, top)
+		if top == node {
+			break
+		}
+	}
+	// Popping reverses the order the nodes were found in.
+	for i, j := 0, len(component)-1; i < j; i, j = i+1, j-1 {
+		component[i], component[j] = component[j], component[i]
+	}
+	s.components = append(s.components, component)
+}
+
+// A cycle of child edges.
+type Cycle struct {
+	// The IDs of the nodes along the cycle, starting and ending with the same node.
+	IDs []string
+	// The IDs of every node in the strongly connected component containing the cycle.  Each of them is part of a cycle
+	// through the others, so they all need to be looked at to remove the cycles.
+	Component []string
+}
+
+func (c Cycle) String() string {
+	return strings.Join(c.IDs, " -> ")
+}
+
+// Find the cycles in the graphs containing the provided nodes.  One of the shortest cycles is reported for each
+// strongly connected component that has one, in the order of StronglyConnectedComponents.
+func FindCycles[T any](nodes ...Graph[T]) []Cycle {
+	var result []Cycle
+	for _, component := range StronglyConnectedComponents(nodes...) {
+		start := component[0]
+		if len(component) == 1 && !containsNode(start.GetChildren(), start) {
+			continue
+		}
+		members := make(map[Graph[T]]bool)
+		cycle := Cycle{}
+		for _, node := range component {
+			members[node] = true
+			cycle.Component = append(cycle.Component, node.GetID())
+		}
+		for _, node := range shortestCycle(start, members) {
+			cycle.IDs = append(cycle.IDs, node.GetID())
+		}
+		result = append(result, cycle)
+	}
+	return result
+}
+
+// Find one of the shortest cycles through start, staying within members.  Start must be part of a cycle.
+func shortestCycle[T any](start Graph[T], members map[Graph[T]]bool) []Graph[T] {
+	previous := make(map[Graph[T]]Graph[T])
+	queue := []Graph[T]{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, child := range node.GetChildren() {
+			if child == start {
+				path := []Graph[T]{start}
+				for n := node; n != start; n = previous[n] {
+					path = append(path, n)
+				}
+				path = append(path, start)
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return path
+			}
+			if _, seen := previous[child]; !seen && members[child] {
+				previous[child] = node
+				queue = append(queue, child)
+			}
+		}
+	}
+	return nil
+}
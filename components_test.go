@@ -0,0 +1,62 @@
+package girraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getComponentIDs[T any](components [][]Graph[T]) [][]string {
+	var result [][]string
+	for _, component := range components {
+		var ids []string
+		for _, node := range component {
+			ids = append(ids, node.GetID())
+		}
+		result = append(result, ids)
+	}
+	return result
+}
+
+// A -> B -> C -> A, C -> D -> D, and X -> B with X not below A.
+func getCyclicGraphFixture() (Graph[string], Graph[string]) {
+	a := MakeGraph[string]().SetID("A")
+	b := MakeGraph[string]().SetID("B")
+	c := MakeGraph[string]().SetID("C")
+	d := MakeGraph[string]().SetID("D")
+	x := MakeGraph[string]().SetID("X")
+	a.AddChild(b)
+	b.AddChild(c)
+	c.AddChild(a).AddChild(d)
+	d.AddChild(d)
+	x.AddChild(b)
+	return a, x
+}
+
+func TestConnectedComponents(t *testing.T) {
+	a, x := getCyclicGraphFixture()
+	y := MakeGraph[string]().SetID("Y")
+
+	result := ConnectedComponents(a, x, y)
+	assert.Equal(t, [][]string{{"A", "B", "C", "X", "D"}, {"Y"}}, getComponentIDs(result))
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	result := StronglyConnectedComponents(getGraphFixture())
+	assert.Equal(t, [][]string{{"D"}, {"B"}, {"C"}, {"A"}}, getComponentIDs(result))
+
+	a, _ := getCyclicGraphFixture()
+	assert.Equal(t, [][]string{{"D"}, {"A", "B", "C"}, {"X"}}, getComponentIDs(StronglyConnectedComponents(a)))
+}
+
+func TestFindCycles(t *testing.T) {
+	assert.Empty(t, FindCycles(getGraphFixture()))
+
+	a, _ := getCyclicGraphFixture()
+	result := FindCycles(a)
+	assert.Equal(t, []Cycle{
+		{IDs: []string{"D", "D"}, Component: []string{"D"}},
+		{IDs: []string{"A", "B", "C", "A"}, Component: []string{"A", "B", "C"}},
+	}, result)
+	assert.Equal(t, "A -> B -> C -> A", result[1].String())
+}
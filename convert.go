@@ -0,0 +1,99 @@
+package girraph
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrMultipleParents = errors.New("node has multiple parents")
+
+// How GraphToTree handles a node that is reached through more than one parent.
+type MultiParentStrategy int
+
+const (
+	// Keep the node under the first parent it is reached through, depth-first, and drop the other edges.
+	SpanningTree MultiParentStrategy = iota
+	// Copy the node and its descendants under each parent, giving the copies new IDs from the ID generator.
+	DuplicateWithFreshIDs
+	// Copy the node and its descendants under each parent, suffixing the IDs of the copies with "#2", "#3", and so on.
+	DuplicateWithSuffixedIDs
+	// Fail with ErrMultipleParents.
+	FailOnMultipleParents
+)
+
+// Convert a tree to a graph with the same IDs, meta and child order.
+func TreeToGraph[T any](root Tree[T]) Graph[T] {
+	result := &graph[T]{
+		ID:       root.GetID(),
+		Meta:     root.GetMeta(),
+		Children: []Graph[T]{},
+		parents:  []Graph[T]{},
+	}
+	for _, child := range root.GetChildren() {
+		result.AddChild(TreeToGraph(child))
+	}
+	return result
+}
+
+// Convert a graph to a tree, using the strategy to handle nodes with multiple parents.  The options are used to
+// generate IDs for DuplicateWithFreshIDs.
+func GraphToTree[T any](root Graph[T], strategy MultiParentStrategy, options ...Option) (Tree[T], error) {
+	c := &treeConversion[T]{
+		strategy:    strategy,
+		options:     options,
+		occurrences: make(map[Graph[T]]int),
+		onPath:      make(map[Graph[T]]bool),
+	}
+	return c.convert(root, false)
+}
+
+type treeConversion[T any] struct {
+	strategy    MultiParentStrategy
+	options     []Option
+	occurrences map[Graph[T]]int
+	onPath      map[Graph[T]]bool
+}
+
+func (c *treeConversion[T]) convert(node Graph[T], copied bool) (Tree[T], error) {
+	if c.onPath[node] {
+		return nil, fmt.Errorf("%w: node %q is its own descendant", ErrCycle, node.GetID())
+	}
+	c.onPath[node] = true
+	defer delete(c.onPath, node)
+
+	c.occurrences[node]++
+	occurrence := c.occurrences[node]
+	if occurrence > 1 && !copied {
+		copied = true
+		if c.strategy == FailOnMultipleParents {
+			return nil, fmt.Errorf("%w: %q", ErrMultipleParents, node.GetID())
+		}
+	}
+
+	id := node.GetID()
+	if copied {
+		switch c.strategy {
+		case DuplicateWithFreshIDs:
+			id = newID(c.options)
+		case DuplicateWithSuffixedIDs:
+			id = fmt.Sprintf("%s#%d", id, occurrence)
+		}
+	}
+
+	result := &TreeNode[T]{
+		ID:       id,
+		Meta:     node.GetMeta(),
+		Children: []Tree[T]{},
+	}
+	for _, child := range node.GetChildren() {
+		if c.strategy == SpanningTree && c.occurrences[child] > 0 {
+			continue
+		}
+		converted, err := c.convert(child, copied)
+		if err != nil {
+			return nil, err
+		}
+		result.AddChild(converted)
+	}
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package girraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getIDs[T Node[T]](root T) []string {
+	var result []string
+	Traverse(root, func(node T) {
+		result = append(result, node.GetID())
+	})
+	return result
+}
+
+func TestTreeToGraph(t *testing.T) {
+	tree := getTreeFixture()
+	graph := TreeToGraph(tree)
+
+	assert.Empty(t, Validate(graph))
+	assert.Equal(t, getIDs(tree), getIDs(graph))
+	assert.Equal(t, "node D", FindNodesByID(graph, "D")[0].GetMeta().GetName())
+
+	treeJSON, err := tree.JSON()
+	require.Nil(t, err)
+	graphJSON, err := graph.JSON()
+	require.Nil(t, err)
+	assert.JSONEq(t, string(treeJSON), string(graphJSON))
+}
+
+func TestGraphToTree_SpanningTree(t *testing.T) {
+	tree, err := GraphToTree(getGraphFixture(), SpanningTree)
+	require.Nil(t, err)
+
+	assert.Empty(t, Validate(tree))
+	assert.Equal(t, []string{"A", "B", "D", "C"}, getIDs(tree))
+	assert.Equal(t, "B", FindNodesByID(tree, "D")[0].GetParent().GetID())
+}
+
+func TestGraphToTree_DuplicateWithSuffixedIDs(t *testing.T) {
+	graph := getGraphFixture()
+	FindNodesByID(graph, "D")[0].AddChild(MakeGraph[CustomGraph]().SetID("E"))
+
+	tree, err := GraphToTree(graph, DuplicateWithSuffixedIDs)
+	require.Nil(t, err)
+
+	assert.Empty(t, Validate(tree))
+	assert.Equal(t, []string{"A", "B", "D", "E", "C", "D#2", "E#2"}, getIDs(tree))
+}
+
+func TestGraphToTree_DuplicateWithFreshIDs(t *testing.T) {
+	tree, err := GraphToTree(getGraphFixture(), DuplicateWithFreshIDs, WithIDGenerator(NewSequentialGenerator("copy-")))
+	require.Nil(t, err)
+
+	assert.Empty(t, Validate(tree))
+	assert.Equal(t, []string{"A", "B", "D", "C", "copy-1"}, getIDs(tree))
+	assert.Equal(t, "node D", FindNodesByID(tree, "copy-1")[0].GetMeta().GetName())
+}
+
+func TestGraphToTree_FailOnMultipleParents(t *testing.T) {
+	_, err := GraphToTree(getGraphFixture(), FailOnMultipleParents)
+	assert.True(t, errors.Is(err, ErrMultipleParents))
+}
+
+func TestGraphToTree_Cycle(t *testing.T) {
+	graph := getGraphFixture()
+	FindNodesByID(graph, "D")[0].AddChild(graph)
+
+	_, err := GraphToTree(graph, DuplicateWithSuffixedIDs)
+	assert.True(t, errors.Is(err, ErrCycle))
+}
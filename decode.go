@@ -0,0 +1,145 @@
+package girraph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Returned by MetaRegistry when the discriminator of some meta has no registered type.
+var ErrUnknownMetaType = errors.New("unknown meta type")
+
+// Decodes the meta of a node from JSON.  This lets meta be decoded into an interface type, which json.Unmarshal can't
+// do on its own.
+type MetaDecoder[T any] func(data json.RawMessage) (T, error)
+
+// Get a decoder that decodes meta into the value returned by newMeta.  When T is an interface, newMeta must return a
+// pointer to the concrete type, e.g. func() Workflow { return &workflow{} }.  Null meta decodes to the zero value.
+func MetaFactory[T any](newMeta func() T) MetaDecoder[T] {
+	return func(data json.RawMessage) (T, error) {
+		var meta T
+		if isNullJSON(data) {
+			return meta, nil
+		}
+		meta = newMeta()
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return meta, err
+		}
+		return meta, nil
+	}
+}
+
+// Maps the values of a discriminator field in meta to the concrete types to decode the meta into.
+type MetaRegistry[T any] struct {
+	field string
+	types map[string]func() T
+}
+
+// Make a registry that chooses the type of each meta by the string value of the provided field.  As with
+// json.Unmarshal, an exact match of the field name is preferred, but a case-insensitive match is accepted.
+func NewMetaRegistry[T any](field string) *MetaRegistry[T] {
+	return &MetaRegistry[T]{
+		field: field,
+		types: make(map[string]func() T),
+	}
+}
+
+// Decode meta whose discriminator has the provided value into the value returned by newMeta, as with MetaFactory.
+// Registering the empty value sets the type of meta without the field.
+func (r *MetaRegistry[T]) Register(value string, newMeta func() T) *MetaRegistry[T] {
+	r.types[value] = newMeta
+	return r
+}
+
+// Decode meta into the type registered for its discriminator.  Its signature matches MetaDecoder, so the method can be
+// passed wherever one is expected.
+func (r *MetaRegistry[T]) Decode(data json.RawMessage) (T, error) {
+	var meta T
+	if isNullJSON(data) {
+		return meta, nil
+	}
+	value, err := r.discriminator(data)
+	if err != nil {
+		return meta, err
+	}
+	newMeta, ok := r.types[value]
+	if !ok {
+		return meta, fmt.Errorf("%w: %s %q", ErrUnknownMetaType, r.field, value)
+	}
+	return MetaFactory(newMeta)(data)
+}
+
+func (r *MetaRegistry[T]) discriminator(data json.RawMessage) (string, error) {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", err
+	}
+	raw, ok := fields[r.field]
+	if !ok {
+		for name, value := range fields {
+			if strings.EqualFold(name, r.field) {
+				raw, ok = value, true
+				break
+			}
+		}
+	}
+	if !ok || isNullJSON(raw) {
+		return "", nil
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", fmt.Errorf("%s must be a string: %w", r.field, err)
+	}
+	return value, nil
+}
+
+func isNullJSON(data json.RawMessage) bool {
+	trimmed := strings.TrimSpace(string(data))
+	return trimmed == "" || trimmed == "null"
+}
+
+// Like GraphFromJSON, but decodes meta with the provided decoder, so that T can be an interface.
+func GraphFromJSONWith[T any](input []byte, decode MetaDecoder[T]) (Graph[T], error) {
+	temp, err := decodeNodeJSON(input, decode)
+	if err != nil {
+		return nil, err
+	}
+	return graphFromNode[T](temp, make(map[string]decodedGraph[T])), nil
+}
+
+// Like TreeFromJSON, but decodes meta with the provided decoder, so that T can be an interface.
+func TreeFromJSONWith[T any](input []byte, decode MetaDecoder[T]) (Tree[T], error) {
+	temp, err := decodeNodeJSON(input, decode)
+	if err != nil {
+		return nil, err
+	}
+	return TreeFromNode[T](temp), nil
+}
+
+func decodeNodeJSON[T any](input []byte, decode MetaDecoder[T]) (*NodeJSON[T], error) {
+	raw := &NodeJSON[json.RawMessage]{}
+	if err := json.Unmarshal(input, raw); err != nil {
+		return nil, err
+	}
+	return convertNodeJSON(raw, decode)
+}
+
+func convertNodeJSON[T any](n *NodeJSON[json.RawMessage], decode MetaDecoder[T]) (*NodeJSON[T], error) {
+	meta, err := decode(n.Meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode meta of node %q: %w", n.ID, err)
+	}
+	result := &NodeJSON[T]{
+		ID:   n.ID,
+		Meta: meta,
+	}
+	for _, child := range n.Children {
+		converted, err := convertNodeJSON(child, decode)
+		if err != nil {
+			return nil, err
+		}
+		result.Children = append(result.Children, converted)
+	}
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package girraph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type taggedGraph struct {
+	Kind string
+	Name string
+	Tags []string
+}
+
+func (g *taggedGraph) SetName(name string) CustomGraph {
+	g.Name = name
+	return g
+}
+
+func (g *taggedGraph) GetName() string {
+	return g.Name
+}
+
+func getCustomGraphRegistry() *MetaRegistry[CustomGraph] {
+	return NewMetaRegistry[CustomGraph]("kind").
+		Register("", func() CustomGraph { return &customGraph{} }).
+		Register("tagged", func() CustomGraph { return &taggedGraph{} })
+}
+
+func TestGraphFromJSONWith_Factory(t *testing.T) {
+	expected, err := getGraphFixture().JSON()
+	require.Nil(t, err)
+
+	result, err := GraphFromJSONWith(expected, MetaFactory(func() CustomGraph {
+		return &customGraph{}
+	}))
+	require.Nil(t, err)
+	assert.Equal(t, "node A", result.GetMeta().GetName())
+
+	// Nodes written out once per parent are shared again.
+	b, c := result.GetChildren()[0], result.GetChildren()[1]
+	assert.Same(t, b.GetChildren()[0], c.GetChildren()[0])
+	assert.Len(t, b.GetChildren()[0].GetParents(), 2)
+
+	actual, err := result.JSON()
+	require.Nil(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestGraphFromJSONWith_NullMeta(t *testing.T) {
+	result, err := GraphFromJSONWith([]byte(`{"ID":"A","Meta":null,"Children":[{"ID":"B"}]}`), MetaFactory(func() CustomGraph {
+		return &customGraph{}
+	}))
+	require.Nil(t, err)
+	assert.Nil(t, result.GetMeta())
+	assert.Nil(t, result.GetChildren()[0].GetMeta())
+}
+
+func TestGraphFromJSONWith_Registry(t *testing.T) {
+	input := `{"ID":"A","Meta":{"Name":"node A"},"Children":[
+		{"ID":"B","Meta":{"Kind":"tagged","Name":"node B","Tags":["x"]},"Children":null}
+	]}`
+	result, err := GraphFromJSONWith([]byte(input), getCustomGraphRegistry().Decode)
+	require.Nil(t, err)
+
+	assert.IsType(t, &customGraph{}, result.GetMeta())
+	assert.Equal(t, "node A", result.GetMeta().GetName())
+	b := result.GetChildren()[0].GetMeta()
+	require.IsType(t, &taggedGraph{}, b)
+	assert.Equal(t, "node B", b.GetName())
+	assert.Equal(t, []string{"x"}, b.(*taggedGraph).Tags)
+}
+
+func TestGraphFromJSONWith_UnknownType(t *testing.T) {
+	input := `{"ID":"A","Meta":{"Name":"node A"},"Children":[{"ID":"B","Meta":{"kind":"other"}}]}`
+	_, err := GraphFromJSONWith([]byte(input), getCustomGraphRegistry().Decode)
+	require.NotNil(t, err)
+	assert.ErrorIs(t, err, ErrUnknownMetaType)
+	assert.Equal(t, `failed to decode meta of node "B": unknown meta type: kind "other"`, err.Error())
+}
+
+func TestMetaRegistry_Decode_NonString(t *testing.T) {
+	_, err := getCustomGraphRegistry().Decode(json.RawMessage(`{"kind":1}`))
+	assert.NotNil(t, err)
+}
+
+func TestTreeFromJSONWith(t *testing.T) {
+	expected, err := getTreeFixture().JSON()
+	require.Nil(t, err)
+
+	result, err := TreeFromJSONWith(expected, MetaFactory(func() CustomTree {
+		return &customTree{}
+	}))
+	require.Nil(t, err)
+
+	actual, err := result.JSON()
+	require.Nil(t, err)
+	assert.Equal(t, expected, actual)
+	assert.Same(t, result, result.GetChildren()[0].GetParent())
+}
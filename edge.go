@@ -0,0 +1,200 @@
+package girraph
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Identifies the edge from a parent to one of its children.
+type EdgeKey struct {
+	Parent string
+	Child  string
+}
+
+// The attributes of an edge.  Order is used by SortChildren; Meta holds any other attributes.
+type Edge[E any] struct {
+	Parent string
+	Child  string
+	Label  string
+	Weight float64
+	Order  int
+	Meta   E
+}
+
+func (e Edge[E]) Key() EdgeKey {
+	return EdgeKey{
+		Parent: e.Parent,
+		Child:  e.Child,
+	}
+}
+
+// Attributes for the edges of a graph or tree, keyed by parent and child ID.  Edges without attributes don't need an
+// entry.  Entries are not updated when nodes are re-parented or their IDs change.  The zero value is ready to use.
+type Edges[E any] struct {
+	edges map[EdgeKey]Edge[E]
+}
+
+func MakeEdges[E any]() *Edges[E] {
+	return &Edges[E]{
+		edges: make(map[EdgeKey]Edge[E]),
+	}
+}
+
+func (e *Edges[E]) Set(edge Edge[E]) *Edges[E] {
+	if e.edges == nil {
+		e.edges = make(map[EdgeKey]Edge[E])
+	}
+	e.edges[edge.Key()] = edge
+	return e
+}
+
+// Get the attributes of an edge.  If the edge has none, a zero edge is returned with its Parent and Child set.
+func (e *Edges[E]) Get(parentID, childID string) (Edge[E], bool) {
+	key := EdgeKey{
+		Parent: parentID,
+		Child:  childID,
+	}
+	edge, ok := e.edges[key]
+	if !ok {
+		edge.Parent = parentID
+		edge.Child = childID
+	}
+	return edge, ok
+}
+
+func (e *Edges[E]) Delete(parentID, childID string) *Edges[E] {
+	delete(e.edges, EdgeKey{
+		Parent: parentID,
+		Child:  childID,
+	})
+	return e
+}
+
+func (e *Edges[E]) Len() int {
+	return len(e.edges)
+}
+
+// Get every edge, ordered by parent ID, then Order, then child ID.
+func (e *Edges[E]) All() []Edge[E] {
+	result := make([]Edge[E], 0, len(e.edges))
+	for _, edge := range e.edges {
+		result = append(result, edge)
+	}
+	sortEdges(result)
+	return result
+}
+
+// Get the edges from a parent, ordered by Order, then child ID.
+func (e *Edges[E]) From(parentID string) []Edge[E] {
+	var result []Edge[E]
+	for key, edge := range e.edges {
+		if key.Parent == parentID {
+			result = append(result, edge)
+		}
+	}
+	sortEdges(result)
+	return result
+}
+
+// Get the weight of an edge, or 0 if it has no attributes.
+func (e *Edges[E]) Weight(parentID, childID string) float64 {
+	edge, _ := e.Get(parentID, childID)
+	return edge.Weight
+}
+
+func (e *Edges[E]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.All())
+}
+
+func (e *Edges[E]) UnmarshalJSON(input []byte) error {
+	var edges []Edge[E]
+	err := json.Unmarshal(input, &edges)
+	if err != nil {
+		return err
+	}
+	e.edges = make(map[EdgeKey]Edge[E], len(edges))
+	for _, edge := range edges {
+		e.Set(edge)
+	}
+	return nil
+}
+
+func sortEdges[E any](edges []Edge[E]) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Parent != edges[j].Parent {
+			return edges[i].Parent < edges[j].Parent
+		}
+		if edges[i].Order != edges[j].Order {
+			return edges[i].Order < edges[j].Order
+		}
+		return edges[i].Child < edges[j].Child
+	})
+}
+
+// Call the callback once for each edge below root, with the edge attributes.  Shared nodes are only descended into
+// once.
+func TraverseEdges[T Node[T], E any](root T, edges *Edges[E], callback func(parent T, child T, edge Edge[E])) {
+	traverseEdges(root, edges, callback, make(map[any]bool))
+}
+
+func traverseEdges[T Node[T], E any](node T, edges *Edges[E], callback func(T, T, Edge[E]), visited map[any]bool) {
+	visited[node] = true
+	for _, child := range node.GetChildren() {
+		edge, _ := edges.Get(node.GetID(), child.GetID())
+		callback(node, child, edge)
+		if !visited[child] {
+			traverseEdges(child, edges, callback, visited)
+		}
+	}
+}
+
+// Reorder the children of every node below root by the Order of their edges.  Children without edge attributes are
+// treated as Order 0 and otherwise keep their relative order.
+func SortChildren[T Node[T], E any](root T, edges *Edges[E]) {
+	visited := make(map[any]bool)
+	Traverse(root, func(node T) {
+		if visited[node] {
+			return
+		}
+		visited[node] = true
+		children := append([]T{}, node.GetChildren()...)
+		sort.SliceStable(children, func(i, j int) bool {
+			return edges.order(node, children[i]) < edges.order(node, children[j])
+		})
+		node.SetChildren(children)
+	})
+}
+
+func (e *Edges[E]) order(parent, child interface{ GetID() string }) int {
+	edge, _ := e.Get(parent.GetID(), child.GetID())
+	return edge.Order
+}
+
+type graphWithEdgesJSON[T any, E any] struct {
+	Graph T
+	Edges *Edges[E]
+}
+
+// Encode a graph together with its edge attributes.
+func GraphWithEdgesJSON[T any, E any](root Graph[T], edges *Edges[E]) ([]byte, error) {
+	return json.Marshal(graphWithEdgesJSON[Graph[T], E]{
+		Graph: root,
+		Edges: edges,
+	})
+}
+
+// Decode a graph and its edge attributes encoded by GraphWithEdgesJSON.
+func GraphWithEdgesFromJSON[T any, E any](input []byte) (Graph[T], *Edges[E], error) {
+	temp := graphWithEdgesJSON[json.RawMessage, E]{
+		Edges: MakeEdges[E](),
+	}
+	err := json.Unmarshal(input, &temp)
+	if err != nil {
+		return nil, nil, err
+	}
+	graph, err := GraphFromJSON[T](temp.Graph)
+	if err != nil {
+		return nil, nil, err
+	}
+	return graph, temp.Edges, nil
+}
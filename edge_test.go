@@ -0,0 +1,88 @@
+package girraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type edgeMeta struct {
+	Condition string
+}
+
+func getEdgesFixture() *Edges[edgeMeta] {
+	return MakeEdges[edgeMeta]().
+		Set(Edge[edgeMeta]{Parent: "A", Child: "B", Label: "first", Weight: 1.5, Order: 2}).
+		Set(Edge[edgeMeta]{Parent: "A", Child: "C", Label: "second", Order: 1, Meta: edgeMeta{Condition: "ready"}}).
+		Set(Edge[edgeMeta]{Parent: "C", Child: "D", Weight: 3})
+}
+
+func TestEdges_Get(t *testing.T) {
+	edges := getEdgesFixture()
+
+	edge, ok := edges.Get("A", "C")
+	require.True(t, ok)
+	assert.Equal(t, "second", edge.Label)
+	assert.Equal(t, "ready", edge.Meta.Condition)
+
+	edge, ok = edges.Get("B", "D")
+	require.False(t, ok)
+	assert.Equal(t, EdgeKey{Parent: "B", Child: "D"}, edge.Key())
+
+	assert.Equal(t, 3.0, edges.Weight("C", "D"))
+	assert.Equal(t, 0.0, edges.Weight("B", "D"))
+
+	edges.Delete("C", "D")
+	assert.Equal(t, 2, edges.Len())
+}
+
+func TestEdges_ZeroValue(t *testing.T) {
+	var edges Edges[edgeMeta]
+	_, ok := edges.Get("A", "B")
+	assert.False(t, ok)
+
+	edges.Set(Edge[edgeMeta]{Parent: "A", Child: "B", Weight: 2})
+	assert.Equal(t, 1, edges.Len())
+	assert.Equal(t, 2.0, edges.Weight("A", "B"))
+}
+
+func TestEdges_From(t *testing.T) {
+	edges := getEdgesFixture().From("A")
+	require.Len(t, edges, 2)
+	assert.Equal(t, "C", edges[0].Child)
+	assert.Equal(t, "B", edges[1].Child)
+}
+
+func TestTraverseEdges(t *testing.T) {
+	var labels []string
+	TraverseEdges(getGraphFixture(), getEdgesFixture(), func(parent, child Graph[CustomGraph], edge Edge[edgeMeta]) {
+		labels = append(labels, parent.GetID()+child.GetID()+":"+edge.Label)
+	})
+	assert.Equal(t, []string{"AB:first", "BD:", "AC:second", "CD:"}, labels)
+}
+
+func TestSortChildren(t *testing.T) {
+	graph := getGraphFixture()
+	SortChildren(graph, getEdgesFixture())
+
+	children := graph.GetChildren()
+	require.Len(t, children, 2)
+	assert.Equal(t, "C", children[0].GetID())
+	assert.Equal(t, "B", children[1].GetID())
+	assert.Empty(t, Validate(graph))
+}
+
+func TestGraphWithEdgesJSON(t *testing.T) {
+	expected, err := GraphWithEdgesJSON(getGraphFixture(), getEdgesFixture())
+	require.Nil(t, err)
+
+	graph, edges, err := GraphWithEdgesFromJSON[*customGraph, edgeMeta](expected)
+	require.Nil(t, err)
+	assert.Equal(t, "node A", graph.GetMeta().GetName())
+	assert.Equal(t, getEdgesFixture().All(), edges.All())
+
+	result, err := GraphWithEdgesJSON(graph, edges)
+	require.Nil(t, err)
+	assert.Equal(t, expected, result)
+}
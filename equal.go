@@ -0,0 +1,262 @@
+package girraph
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Options for Equal, Compare and Isomorphic.
+type EqualOptions[M any] struct {
+	// Match children regardless of their order.  Children are grouped by a digest of their subtrees first, so that
+	// each child is only tried against the children that could equal it.
+	IgnoreChildOrder bool
+	// Compare structure and meta only, so that nodes match regardless of their IDs.
+	IgnoreIDs bool
+	// Compares meta; reflect.DeepEqual is used by default.
+	Meta func(a, b M) bool
+}
+
+// The first difference found between two graphs or trees.
+type Mismatch struct {
+	// The IDs of the nodes from each root to the nodes that differ.
+	A       NodePath
+	B       NodePath
+	Message string
+}
+
+func (m *Mismatch) Error() string {
+	return fmt.Sprintf("%s and %s: %s", m.A, m.B, m.Message)
+}
+
+// Check whether the nodes below a and b are equal.  Nodes must be shared the same way in both: a node with two parents
+// doesn't equal two copies of it.
+func Equal[T MetaNode[T, M], M any](a, b T, options EqualOptions[M]) bool {
+	return Compare(a, b, options) == nil
+}
+
+// Compare the nodes below a and b, returning the first difference found, or nil if they are equal.
+func Compare[T MetaNode[T, M], M any](a, b T, options EqualOptions[M]) *Mismatch {
+	// Custom meta comparisons can match meta that encodes differently, so meta is only part of the match keys when it
+	// is compared with reflect.DeepEqual.
+	keyMeta := options.Meta == nil
+	if options.Meta == nil {
+		options.Meta = func(a, b M) bool {
+			return reflect.DeepEqual(a, b)
+		}
+	}
+	c := &comparison[T, M]{
+		options: options,
+		ab:      make(map[any]T),
+		ba:      make(map[any]T),
+		keyMeta: keyMeta,
+		keys:    make(map[any]matchKey),
+		keyPath: make(map[any]bool),
+	}
+	return c.compare(a, b)
+}
+
+// Check whether the nodes below a and b have the same structure and meta, regardless of their IDs, returning the first
+// difference found, or nil if they do.
+func Isomorphic[T MetaNode[T, M], M any](a, b T, options EqualOptions[M]) *Mismatch {
+	options.IgnoreIDs = true
+	return Compare(a, b, options)
+}
+
+type comparison[T MetaNode[T, M], M any] struct {
+	options EqualOptions[M]
+	// The nodes of each graph matched with the nodes of the other.
+	ab    map[any]T
+	ba    map[any]T
+	trail []T
+	pathA NodePath
+	pathB NodePath
+	// The match keys of the nodes, used to group children when their order is ignored.
+	keyMeta bool
+	keys    map[any]matchKey
+	keyPath map[any]bool
+}
+
+// A digest of everything about a node that must be the same for it to match another node: its ID, unless IDs are
+// ignored, its meta, unless a custom comparison is used, and the keys of its children in sorted order.
+type matchKey struct {
+	digest Digest
+	// Whether the node can reach a cycle.  The keys of such nodes would depend on where the cycle was entered, so their
+	// children are left out.
+	cyclic bool
+}
+
+func (c *comparison[T, M]) mismatch(message string, args ...any) *Mismatch {
+	return &Mismatch{
+		A:       append(NodePath{}, c.pathA...),
+		B:       append(NodePath{}, c.pathB...),
+		Message: fmt.Sprintf(message, args...),
+	}
+}
+
+func (c *comparison[T, M]) compare(x, y T) *Mismatch {
+	c.pathA = append(c.pathA, x.GetID())
+	c.pathB = append(c.pathB, y.GetID())
+	defer func() {
+		c.pathA = c.pathA[:len(c.pathA)-1]
+		c.pathB = c.pathB[:len(c.pathB)-1]
+	}()
+
+	// Nodes reached again, through another parent or a cycle, must be matched with the same node as before.
+	if matched, ok := c.ab[x]; ok {
+		if any(matched) == any(y) {
+			return nil
+		}
+		return c.mismatch("nodes are shared differently: %q was already matched with %q", x.GetID(), matched.GetID())
+	}
+	if matched, ok := c.ba[y]; ok {
+		return c.mismatch("nodes are shared differently: %q was already matched with %q", y.GetID(), matched.GetID())
+	}
+
+	if !c.options.IgnoreIDs && x.GetID() != y.GetID() {
+		return c.mismatch("IDs differ")
+	}
+	if !c.options.Meta(x.GetMeta(), y.GetMeta()) {
+		return c.mismatch("meta differs")
+	}
+	xc, yc := x.GetChildren(), y.GetChildren()
+	if len(xc) != len(yc) {
+		return c.mismatch("%d children and %d children", len(xc), len(yc))
+	}
+
+	c.ab[x] = y
+	c.ba[y] = x
+	c.trail = append(c.trail, x)
+	if c.options.IgnoreChildOrder {
+		return c.compareUnordered(xc, yc)
+	}
+	for i := range xc {
+		if m := c.compare(xc[i], yc[i]); m != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+// Match the children of x with the children of y regardless of order.  Children with different keys can't match, so
+// if the keys differ the first child of x without a match is compared with the child of y in the same position.
+func (c *comparison[T, M]) compareUnordered(xc, yc []T) *Mismatch {
+	xKeys, yKeys := c.keysOf(xc), c.keysOf(yc)
+	remaining := make(map[Digest]int)
+	for _, key := range yKeys {
+		remaining[key]++
+	}
+	for i, key := range xKeys {
+		if remaining[key] == 0 {
+			mark := len(c.trail)
+			m := c.compare(xc[i], yc[i])
+			c.rollback(mark)
+			if m == nil {
+				m = c.mismatch("no child matches %q", xc[i].GetID())
+			}
+			return m
+		}
+		remaining[key]--
+	}
+	return c.matchUnordered(xc, yc, xKeys, yKeys, make([]bool, len(yc)), 0)
+}
+
+// Match the children of x from index i on with the unused children of y that have the same key, trying each candidate
+// in turn.  If none match, the mismatch with the candidate in the same position, or else the first one, is reported.
+func (c *comparison[T, M]) matchUnordered(xc, yc []T, xKeys, yKeys []Digest, used []bool, i int) *Mismatch {
+	if i == len(xc) {
+		return nil
+	}
+	var result *Mismatch
+	for j := range yc {
+		if used[j] || yKeys[j] != xKeys[i] {
+			continue
+		}
+		mark := len(c.trail)
+		m := c.compare(xc[i], yc[j])
+		if m == nil {
+			used[j] = true
+			if m = c.matchUnordered(xc, yc, xKeys, yKeys, used, i+1); m == nil {
+				return nil
+			}
+			used[j] = false
+		}
+		c.rollback(mark)
+		if result == nil || j == i {
+			result = m
+		}
+	}
+	return result
+}
+
+func (c *comparison[T, M]) keysOf(nodes []T) []Digest {
+	result := make([]Digest, len(nodes))
+	for i, node := range nodes {
+		result[i] = c.key(node).digest
+	}
+	return result
+}
+
+func (c *comparison[T, M]) key(node T) matchKey {
+	if key, ok := c.keys[node]; ok {
+		return key
+	}
+	if c.keyPath[node] {
+		return matchKey{cyclic: true}
+	}
+	c.keyPath[node] = true
+	defer delete(c.keyPath, node)
+
+	children := node.GetChildren()
+	var result matchKey
+	childDigests := make([]Digest, 0, len(children))
+	for _, child := range children {
+		key := c.key(child)
+		result.cyclic = result.cyclic || key.cyclic
+		childDigests = append(childDigests, key.digest)
+	}
+	if result.cyclic {
+		childDigests = nil
+	}
+	sort.Slice(childDigests, func(i, j int) bool {
+		return bytes.Compare(childDigests[i][:], childDigests[j][:]) < 0
+	})
+
+	sum := sha256.New()
+	writePart := func(part []byte) {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(part)))
+		sum.Write(length[:])
+		sum.Write(part)
+	}
+	if !c.options.IgnoreIDs {
+		writePart([]byte(node.GetID()))
+	}
+	var meta []byte
+	if c.keyMeta {
+		// Meta that can't be encoded is left out, which only makes the key less selective.
+		meta, _ = canonicalEncoding(node.GetMeta())
+	}
+	writePart(meta)
+	writePart([]byte(strconv.Itoa(len(children))))
+	for _, digest := range childDigests {
+		sum.Write(digest[:])
+	}
+	sum.Sum(result.digest[:0])
+
+	c.keys[node] = result
+	return result
+}
+
+// Forget the matches made since the trail had the provided length.
+func (c *comparison[T, M]) rollback(mark int) {
+	for _, x := range c.trail[mark:] {
+		delete(c.ba, c.ab[x])
+		delete(c.ab, x)
+	}
+	c.trail = c.trail[:mark]
+}
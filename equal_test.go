@@ -0,0 +1,108 @@
+package girraph
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEqual(t *testing.T) {
+	options := EqualOptions[CustomGraph]{}
+	assert.True(t, Equal(getGraphFixture(), getGraphFixture(), options))
+
+	changed := getGraphFixture()
+	FindNodesByID(changed, "D")[0].SetMeta(&customGraph{Name: "NODE D"})
+	m := Compare(getGraphFixture(), changed, options)
+	require.NotNil(t, m)
+	assert.Equal(t, "/A/B/D and /A/B/D: meta differs", m.Error())
+
+	m = Compare(getGraphFixture(), changed, EqualOptions[CustomGraph]{
+		Meta: func(a, b CustomGraph) bool {
+			return strings.EqualFold(a.GetName(), b.GetName())
+		},
+	})
+	assert.Nil(t, m, "meta is compared with the custom comparator")
+}
+
+func TestEqual_ChildOrder(t *testing.T) {
+	reordered := getGraphFixture()
+	reordered.SetChildren([]Graph[CustomGraph]{reordered.GetChildren()[1], reordered.GetChildren()[0]})
+
+	m := Compare(getGraphFixture(), reordered, EqualOptions[CustomGraph]{})
+	require.NotNil(t, m)
+	assert.Equal(t, "/A/B and /A/C: IDs differ", m.Error())
+
+	assert.True(t, Equal(getGraphFixture(), reordered, EqualOptions[CustomGraph]{
+		IgnoreChildOrder: true,
+	}))
+}
+
+func TestEqual_Sharing(t *testing.T) {
+	// Same JSON as the fixture, but with D copied instead of shared.
+	copied := getGraphFixture()
+	c := FindNodesByID(copied, "C")[0]
+	c.SetChildren([]Graph[CustomGraph]{MakeGraph[CustomGraph]().SetID("D").SetMeta(&customGraph{Name: "node D"})})
+
+	fixtureJSON, err := getGraphFixture().JSON()
+	require.Nil(t, err)
+	copiedJSON, err := copied.JSON()
+	require.Nil(t, err)
+	assert.Equal(t, string(fixtureJSON), string(copiedJSON))
+
+	m := Compare(getGraphFixture(), copied, EqualOptions[CustomGraph]{})
+	require.NotNil(t, m)
+	assert.Equal(t, `/A/C/D and /A/C/D: nodes are shared differently: "D" was already matched with "D"`, m.Error())
+}
+
+func TestIsomorphic(t *testing.T) {
+	renamed := getTreeFixture()
+	Traverse(renamed, func(node Tree[CustomTree]) {
+		node.SetID(strings.ToLower(node.GetID()))
+	})
+	options := EqualOptions[CustomTree]{}
+	assert.False(t, Equal(getTreeFixture(), renamed, options))
+	assert.Nil(t, Isomorphic(getTreeFixture(), renamed, options))
+
+	c := renamed.GetChildren()[1]
+	c.AddChild(MakeTree[CustomTree]().SetID("e"))
+	m := Isomorphic(getTreeFixture(), renamed, options)
+	require.NotNil(t, m)
+	assert.Equal(t, "/A/C and /a/c: 1 children and 2 children", m.Error())
+}
+
+func TestIsomorphic_IgnoreChildOrder(t *testing.T) {
+	a := MakeTree[string]().SetID("1").SetMeta("root").
+		AddChild(MakeTree[string]().SetID("2").SetMeta("x").AddChild(MakeTree[string]().SetID("3").SetMeta("y"))).
+		AddChild(MakeTree[string]().SetID("4").SetMeta("x").AddChild(MakeTree[string]().SetID("5").SetMeta("z")))
+	b := MakeTree[string]().SetID("a").SetMeta("root").
+		AddChild(MakeTree[string]().SetID("b").SetMeta("x").AddChild(MakeTree[string]().SetID("c").SetMeta("z"))).
+		AddChild(MakeTree[string]().SetID("d").SetMeta("x").AddChild(MakeTree[string]().SetID("e").SetMeta("y")))
+
+	options := EqualOptions[string]{}
+	m := Isomorphic(a, b, options)
+	require.NotNil(t, m)
+	assert.Equal(t, "/1/2/3 and /a/b/c: meta differs", m.Error())
+
+	options.IgnoreChildOrder = true
+	assert.Nil(t, Isomorphic(a, b, options), "children with the same meta are matched by what is below them")
+}
+
+func TestIsomorphic_ManyAlikeChildren(t *testing.T) {
+	// Trying every assignment of the alike children would take 11! attempts before giving up on the last one.
+	a, b := MakeTree[string]().SetID("a").SetMeta("root"), MakeTree[string]().SetID("b").SetMeta("root")
+	for i := 0; i < 12; i++ {
+		a.AddChild(MakeTree[string]().SetID(fmt.Sprintf("a%d", i)).SetMeta("x"))
+		meta := "x"
+		if i == 11 {
+			meta = "y"
+		}
+		b.AddChild(MakeTree[string]().SetID(fmt.Sprintf("b%d", i)).SetMeta(meta))
+	}
+
+	m := Isomorphic(a, b, EqualOptions[string]{IgnoreChildOrder: true})
+	require.NotNil(t, m)
+	assert.Equal(t, "/a/a11 and /b/b11: meta differs", m.Error())
+}
@@ -0,0 +1,40 @@
+package filesystem
+
+import "github.com/68696c6c/girraph"
+
+// Get the names of the directories from the root down to dir, e.g. for rendering breadcrumbs.
+func GetBreadcrumbs(dir girraph.Tree[Directory]) []string {
+	result := make([]string, girraph.Depth(dir)+1)
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = dir.GetMeta().GetName()
+		dir = dir.GetParent()
+	}
+	return result
+}
+
+// An entry in a directory listing.
+type Entry struct {
+	Name      string
+	Directory bool
+
+	// For directories, the number of directories below the entry.
+	Subdirectories int
+}
+
+// List the subdirectories of dir, followed by its files.
+func GetListing(dir girraph.Tree[Directory]) []Entry {
+	var result []Entry
+	for _, child := range dir.GetChildren() {
+		result = append(result, Entry{
+			Name:           child.GetMeta().GetName(),
+			Directory:      true,
+			Subdirectories: girraph.SubtreeSize(child) - 1,
+		})
+	}
+	for _, f := range dir.GetMeta().GetFiles() {
+		result = append(result, Entry{
+			Name: f.GetFullName(),
+		})
+	}
+	return result
+}
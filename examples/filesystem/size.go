@@ -0,0 +1,22 @@
+package filesystem
+
+import "github.com/68696c6c/girraph"
+
+// Get the total size of the file contents in dir and its subdirectories, and the size of each directory by ID.
+func GetTotalSize(dir girraph.Tree[Directory]) (int, map[string]int) {
+	return girraph.Fold(dir, getDirectorySize, func(node girraph.Tree[Directory], children []int) int {
+		total := getDirectorySize(node)
+		for _, size := range children {
+			total += size
+		}
+		return total
+	})
+}
+
+func getDirectorySize(dir girraph.Tree[Directory]) int {
+	size := 0
+	for _, f := range dir.GetMeta().GetFiles() {
+		size += len(f.GetContents())
+	}
+	return size
+}
@@ -40,6 +40,36 @@ func TestDirectory_JSON(t *testing.T) {
 	// require.False(t, true)
 }
 
+func TestGetBreadcrumbs(t *testing.T) {
+	tree := getDirectoryFixture()
+	dirD := tree.GetChildren()[1].GetChildren()[0]
+	assert.Equal(t, []string{"A", "C", "D"}, GetBreadcrumbs(dirD))
+	assert.Equal(t, []string{"A"}, GetBreadcrumbs(tree))
+}
+
+func TestGetListing(t *testing.T) {
+	result := GetListing(getDirectoryFixture())
+	assert.Equal(t, []Entry{
+		{Name: "B", Directory: true},
+		{Name: "C", Directory: true, Subdirectories: 1},
+		{Name: "one.go"},
+		{Name: "two.go"},
+		{Name: "three.go"},
+	}, result)
+}
+
+func TestGetTotalSize(t *testing.T) {
+	tree := getDirectoryFixture()
+	dirD := tree.GetChildren()[1].GetChildren()[0]
+	dirD.GetMeta().GetFiles()[0].SetContents("key: value")
+	tree.GetMeta().GetFiles()[0].SetContents("package a")
+
+	total, sizes := GetTotalSize(tree)
+	assert.Equal(t, 19, total)
+	assert.Equal(t, 10, sizes[dirD.GetID()])
+	assert.Equal(t, 10, sizes[dirD.GetParent().GetID()])
+}
+
 func getDirectoryFixture() girraph.Tree[Directory] {
 	dirA := MakeDirectory("A")
 	dirA.GetMeta().SetFiles([]*file{
@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -131,6 +132,29 @@ func TestGetUpdatedPlan_Decisions_AddTasks(t *testing.T) {
 	assert.Equal(t, StateTodo, result)
 }
 
+func TestGetCriticalPath(t *testing.T) {
+	workflow := getPlanFixture()
+	estimates := map[TaskType]time.Duration{
+		TaskA: time.Hour,
+		TaskC: 2 * time.Hour,
+		TaskD: time.Hour,
+		TaskH: 30 * time.Minute,
+		TaskN: time.Hour,
+	}
+	girraph.Traverse(workflow, func(node girraph.Graph[Workflow]) {
+		if task := node.GetMeta().GetTask(); task != nil {
+			task.Estimate = estimates[task.Type]
+		}
+	})
+
+	path, duration, err := GetCriticalPath(workflow)
+	require.Nil(t, err)
+	assert.Equal(t, 4*time.Hour, duration)
+	require.Len(t, path, 3)
+	assert.Equal(t, string(TaskC), path[1].GetMeta().GetName())
+	assert.Equal(t, string(TaskN), path[2].GetMeta().GetName())
+}
+
 func TestWorkflow_JSON(t *testing.T) {
 	graph := getPlanFixture()
 
@@ -139,9 +163,9 @@ func TestWorkflow_JSON(t *testing.T) {
 	require.Nil(t, err)
 
 	// Convert back to a graph.
-	graphFromJSON, err := girraph.GraphFromJSON[*workflow](expected)
-	assert.Equal(t, string(TaskA), graphFromJSON.GetMeta().GetName())
+	graphFromJSON, err := WorkflowFromJSON(expected)
 	require.Nil(t, err)
+	assert.Equal(t, string(TaskA), graphFromJSON.GetMeta().GetName())
 
 	// Convert back to JSON.
 	result, err := graphFromJSON.JSON()
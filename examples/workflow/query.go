@@ -1,6 +1,10 @@
 package workflow
 
-import "github.com/68696c6c/girraph"
+import (
+	"time"
+
+	"github.com/68696c6c/girraph"
+)
 
 // Find all task children of the provided node that are not locked behind a decision and have no pre-requisites tasks.
 func GetInitialTasks(workflow girraph.Graph[Workflow]) []girraph.Graph[Workflow] {
@@ -25,6 +29,24 @@ func GetTaskParents(workflow girraph.Graph[Workflow], taskType TaskType) []girra
 	return result
 }
 
+// Find the chain of tasks that takes the longest to complete, assuming the slowest outcome of each decision.  Its total
+// is the minimum time needed to complete the workflow.
+func GetCriticalPath(workflow girraph.Graph[Workflow]) ([]girraph.Graph[Workflow], time.Duration, error) {
+	path, err := girraph.CriticalPath(workflow, girraph.Weights[Workflow]{
+		Node: func(meta Workflow) float64 {
+			task := meta.GetTask()
+			if task == nil {
+				return 0
+			}
+			return float64(task.Estimate)
+		},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return path.Nodes, time.Duration(path.Cost), nil
+}
+
 func findInitialTasks(query *girraph.QueryResult[girraph.Graph[Workflow]], node girraph.Graph[Workflow]) {
 	children := node.GetChildren()
 	if children != nil && len(children) > 0 {
@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"errors"
+	"time"
 
 	"github.com/68696c6c/girraph"
 )
@@ -37,7 +38,8 @@ func MakeCondition(t ConditionType) girraph.Graph[Workflow] {
 type TaskType string
 
 type Task struct {
-	Type TaskType
+	Type     TaskType
+	Estimate time.Duration
 }
 
 func MakeTask(t TaskType) girraph.Graph[Workflow] {
@@ -80,6 +82,12 @@ func MakeWorkflow() girraph.Graph[Workflow] {
 	return girraph.MakeGraph[Workflow]().SetMeta(&workflow{})
 }
 
+func WorkflowFromJSON(input []byte) (girraph.Graph[Workflow], error) {
+	return girraph.GraphFromJSONWith(input, girraph.MetaFactory(func() Workflow {
+		return &workflow{}
+	}))
+}
+
 func (n *workflow) SetName(name string) Workflow {
 	n.Name = name
 	return n
@@ -0,0 +1,130 @@
+package girraph
+
+// How Filter handles nodes that are removed.
+type FilterMode int
+
+const (
+	// Keep the ancestors of matching nodes so that every kept node is still connected to the root.
+	KeepAncestors FilterMode = iota
+	// Remove non-matching nodes and link their nearest kept descendants to their nearest kept ancestors.
+	CollapseRemoved
+)
+
+// Copy the nodes below root that match keep into a new graph or tree of the same type.  The root is always kept.
+func Filter[T MetaNode[T, M], M any](root T, keep func(T) bool, mode FilterMode) (T, error) {
+	f := &filter[T]{
+		keep:     keep,
+		mode:     mode,
+		copies:   make(map[any]T),
+		included: make(map[any]bool),
+		below:    make(map[any][]T),
+		onPath:   make(map[any]bool),
+	}
+	return f.copy(root)
+}
+
+// Copy a graph or tree without the subtrees below root whose top node matches drop.
+func Prune[T MetaNode[T, M], M any](root T, drop func(T) bool) (T, error) {
+	return Filter(root, func(node T) bool {
+		return !drop(node)
+	}, pruneRemoved)
+}
+
+// Used by Prune to cut removed nodes along with their descendants.
+const pruneRemoved FilterMode = -1
+
+type filter[T Node[T]] struct {
+	keep     func(T) bool
+	mode     FilterMode
+	copies   map[any]T
+	included map[any]bool
+	below    map[any][]T
+	onPath   map[any]bool
+}
+
+func (f *filter[T]) copy(node T) (T, error) {
+	if existing, ok := f.copies[node]; ok {
+		return existing, nil
+	}
+	result, err := shallowCopy(node)
+	if err != nil {
+		return result, err
+	}
+	f.copies[node] = result
+	for _, child := range f.children(node) {
+		copied, err := f.copy(child)
+		if err != nil {
+			return result, err
+		}
+		result.AddChild(copied)
+	}
+	return result, nil
+}
+
+// Get the original nodes that become the children of node's copy.
+func (f *filter[T]) children(node T) []T {
+	var result []T
+	for _, child := range node.GetChildren() {
+		switch {
+		case f.mode == KeepAncestors && f.isIncluded(child):
+			result = append(result, child)
+		case f.mode == CollapseRemoved && !f.keep(child):
+			for _, descendant := range f.keptBelow(child) {
+				if !containsNode(result, descendant) {
+					result = append(result, descendant)
+				}
+			}
+		case f.mode != KeepAncestors && f.keep(child):
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// Check whether a node or any of its descendants is kept.
+func (f *filter[T]) isIncluded(node T) bool {
+	if included, ok := f.included[node]; ok {
+		return included
+	}
+	if f.onPath[node] {
+		return false
+	}
+	f.onPath[node] = true
+	defer delete(f.onPath, node)
+
+	included := f.keep(node)
+	for _, child := range node.GetChildren() {
+		if f.isIncluded(child) {
+			included = true
+		}
+	}
+	f.included[node] = included
+	return included
+}
+
+// Get the nearest kept descendants of a removed node.
+func (f *filter[T]) keptBelow(node T) []T {
+	if below, ok := f.below[node]; ok {
+		return below
+	}
+	if f.onPath[node] {
+		return nil
+	}
+	f.onPath[node] = true
+	defer delete(f.onPath, node)
+
+	var result []T
+	for _, child := range node.GetChildren() {
+		found := []T{child}
+		if !f.keep(child) {
+			found = f.keptBelow(child)
+		}
+		for _, descendant := range found {
+			if !containsNode(result, descendant) {
+				result = append(result, descendant)
+			}
+		}
+	}
+	f.below[node] = result
+	return result
+}
@@ -0,0 +1,66 @@
+package girraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func keepIDs[T Node[T]](ids ...string) func(T) bool {
+	return func(node T) bool {
+		for _, id := range ids {
+			if node.GetID() == id {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestFilter_KeepAncestors(t *testing.T) {
+	graph := getGraphFixture()
+
+	result, err := Filter(graph, keepIDs[Graph[CustomGraph]]("D"), KeepAncestors)
+	require.Nil(t, err)
+
+	assert.Empty(t, Validate(result))
+	assert.Equal(t, []string{"A", "B", "D", "C", "D"}, getIDs(result))
+	assert.Len(t, FindNodesByID(result, "D")[0].GetParents(), 2)
+	assert.Len(t, getIDs(graph), 5)
+}
+
+func TestFilter_KeepAncestors_Tree(t *testing.T) {
+	result, err := Filter(getTreeFixture(), keepIDs[Tree[CustomTree]]("D"), KeepAncestors)
+	require.Nil(t, err)
+
+	assert.Empty(t, Validate(result))
+	assert.Equal(t, []string{"A", "C", "D"}, getIDs(result))
+	assert.Equal(t, "node D", FindNodesByID(result, "D")[0].GetMeta().GetName())
+}
+
+func TestFilter_CollapseRemoved(t *testing.T) {
+	result, err := Filter(getGraphFixture(), keepIDs[Graph[CustomGraph]]("D"), CollapseRemoved)
+	require.Nil(t, err)
+
+	assert.Empty(t, Validate(result))
+	assert.Equal(t, []string{"A", "D"}, getIDs(result))
+}
+
+func TestFilter_CollapseRemoved_Tree(t *testing.T) {
+	result, err := Filter(getTreeFixture(), keepIDs[Tree[CustomTree]]("B", "D"), CollapseRemoved)
+	require.Nil(t, err)
+
+	assert.Empty(t, Validate(result))
+	assert.Equal(t, []string{"A", "B", "D"}, getIDs(result))
+	assert.Equal(t, "A", FindNodesByID(result, "D")[0].GetParent().GetID())
+}
+
+func TestPrune(t *testing.T) {
+	result, err := Prune(getGraphFixture(), keepIDs[Graph[CustomGraph]]("B"))
+	require.Nil(t, err)
+
+	assert.Empty(t, Validate(result))
+	assert.Equal(t, []string{"A", "C", "D"}, getIDs(result))
+	assert.Len(t, FindNodesByID(result, "D")[0].GetParents(), 1)
+}
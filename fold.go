@@ -0,0 +1,53 @@
+package girraph
+
+// Compute a result for root from the bottom up.  Leaves are computed with leaf and every other node with combine, from
+// the node and the results of its children in order.  Results are memoized by node, so shared nodes are computed once.
+// They are also returned by ID for inspection; nodes without an ID are left out, and if nodes share an ID only the
+// first one computed is included.  Edges back into the current path are ignored.
+func Fold[T Node[T], R any](root T, leaf func(node T) R, combine func(node T, children []R) R) (R, map[string]R) {
+	f := &fold[T, R]{
+		leaf:    leaf,
+		combine: combine,
+		results: make(map[any]R),
+		byID:    make(map[string]R),
+		onPath:  make(map[any]bool),
+	}
+	return f.fold(root), f.byID
+}
+
+type fold[T Node[T], R any] struct {
+	leaf    func(T) R
+	combine func(T, []R) R
+	results map[any]R
+	byID    map[string]R
+	onPath  map[any]bool
+}
+
+func (f *fold[T, R]) fold(node T) R {
+	if result, ok := f.results[node]; ok {
+		return result
+	}
+	f.onPath[node] = true
+	defer delete(f.onPath, node)
+
+	var result R
+	children := node.GetChildren()
+	if len(children) == 0 {
+		result = f.leaf(node)
+	} else {
+		childResults := make([]R, 0, len(children))
+		for _, child := range children {
+			if !f.onPath[child] {
+				childResults = append(childResults, f.fold(child))
+			}
+		}
+		result = f.combine(node, childResults)
+	}
+	f.results[node] = result
+	if id := node.GetID(); id != "" {
+		if _, ok := f.byID[id]; !ok {
+			f.byID[id] = result
+		}
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package girraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFold(t *testing.T) {
+	calls := make(map[string]int)
+	count := func(node Graph[CustomGraph]) int {
+		calls[node.GetID()]++
+		return 1
+	}
+	result, results := Fold(getGraphFixture(), count, func(node Graph[CustomGraph], children []int) int {
+		total := count(node)
+		for _, child := range children {
+			total += child
+		}
+		return total
+	})
+
+	// Node D is counted once per path, but only computed once.
+	assert.Equal(t, 5, result)
+	assert.Equal(t, map[string]int{"A": 5, "B": 2, "C": 2, "D": 1}, results)
+	assert.Equal(t, 1, calls["D"])
+}
+
+func TestFold_Tree(t *testing.T) {
+	height, _ := Fold(getTreeFixture(), func(Tree[CustomTree]) int {
+		return 0
+	}, func(node Tree[CustomTree], children []int) int {
+		result := 0
+		for _, child := range children {
+			if child+1 > result {
+				result = child + 1
+			}
+		}
+		return result
+	})
+	assert.Equal(t, 2, height)
+}
+
+func TestFold_Cycle(t *testing.T) {
+	graph := getGraphFixture()
+	FindNodesByID(graph, "D")[0].AddChild(graph)
+
+	_, results := Fold(graph, func(Graph[CustomGraph]) int {
+		return 1
+	}, func(node Graph[CustomGraph], children []int) int {
+		return len(children)
+	})
+	assert.Equal(t, 0, results["D"])
+}
+
+func TestFold_EmptyIDs(t *testing.T) {
+	root := MakeGraph[int]().SetID("A").SetChildren([]Graph[int]{
+		MakeGraph[int]().SetID("").SetMeta(5),
+		MakeGraph[int]().SetID("").SetMeta(7),
+	})
+	sum := func(node Graph[int], children []int) int {
+		total := node.GetMeta()
+		for _, child := range children {
+			total += child
+		}
+		return total
+	}
+	result, results := Fold(root, func(node Graph[int]) int { return node.GetMeta() }, sum)
+
+	// Nodes without an ID are still computed separately.
+	assert.Equal(t, 12, result)
+	assert.Equal(t, map[string]int{"A": 12}, results)
+}
@@ -1,9 +1,9 @@
 package girraph
 
 import (
+	"bytes"
 	"encoding/json"
-
-	"github.com/google/uuid"
+	"reflect"
 )
 
 type Graph[T any] interface {
@@ -15,22 +15,33 @@ type Graph[T any] interface {
 }
 
 type graph[T any] struct {
-	ID       string
-	Meta     T
-	Children []Graph[T]
-	parents  []Graph[T]
+	ID        string
+	Meta      T
+	Children  []Graph[T]
+	parents   []Graph[T]
+	cache     nodeCache
+	observers observerList[Graph[T]]
 }
 
-func MakeGraph[T any]() Graph[T] {
+func MakeGraph[T any](options ...Option) Graph[T] {
 	return &graph[T]{
-		ID:       uuid.New().String(),
+		ID:       newID(options),
 		Children: []Graph[T]{},
 		parents:  []Graph[T]{},
 	}
 }
 
 func (g *graph[T]) SetID(id string) Graph[T] {
+	old := g.ID
 	g.ID = id
+	if old != id {
+		emit[Graph[T]](g, Event[Graph[T]]{
+			Type:  IDChanged,
+			Node:  g,
+			OldID: old,
+			NewID: id,
+		})
+	}
 	return g
 }
 
@@ -39,10 +50,17 @@ func (g *graph[T]) GetID() string {
 }
 
 func (g *graph[T]) SetChildren(children []Graph[T]) Graph[T] {
+	old := g.Children
+	orphans := orphanChildren(children)
+	for _, child := range g.Children {
+		child.SetParents(removeNode[Graph[T]](child.GetParents(), g))
+	}
 	for _, child := range children {
 		child.AddParent(g)
 	}
 	g.Children = children
+	g.invalidate()
+	emit[Graph[T]](g, childrenEvents[Graph[T]](g, old, children, orphans)...)
 	return g
 }
 
@@ -51,8 +69,11 @@ func (g *graph[T]) GetChildren() []Graph[T] {
 }
 
 func (g *graph[T]) AddChild(child Graph[T]) Graph[T] {
+	orphan := len(child.GetParents()) == 0
 	child.AddParent(g)
 	g.Children = append(g.Children, child)
+	g.invalidate()
+	emit[Graph[T]](g, addChildEvents[Graph[T]](g, child, len(g.Children)-1, orphan)...)
 	return g
 }
 
@@ -75,10 +96,40 @@ func (g *graph[T]) JSON() ([]byte, error) {
 }
 
 func (g *graph[T]) SetMeta(meta T) Graph[T] {
+	old := g.Meta
 	g.Meta = meta
+	g.invalidate()
+	emit[Graph[T]](g, Event[Graph[T]]{
+		Type:    MetaChanged,
+		Node:    g,
+		OldMeta: old,
+		NewMeta: meta,
+	})
 	return g
 }
 
+func (g *graph[T]) shallowCopy() Graph[T] {
+	return &graph[T]{
+		ID:       g.ID,
+		Meta:     g.Meta,
+		Children: []Graph[T]{},
+		parents:  []Graph[T]{},
+	}
+}
+
+func (g *graph[T]) getCache() *nodeCache {
+	return &g.cache
+}
+
+func (g *graph[T]) getObservers() *observerList[Graph[T]] {
+	return &g.observers
+}
+
+func (g *graph[T]) invalidate() {
+	g.cache = nodeCache{}
+	invalidateParents(g.parents)
+}
+
 func (g *graph[T]) GetMeta() T {
 	return g.Meta
 }
@@ -90,63 +141,22 @@ func SetParents[T any](input Graph[T]) {
 	}
 }
 
-type nodeCount[T any] struct {
-	count int
-	node  Graph[T]
-}
-
 func GraphFromJSON[T any](input []byte) (Graph[T], error) {
 	temp := &NodeJSON[T]{}
 	err := json.Unmarshal(input, temp)
 	if err != nil {
 		return nil, err
 	}
-	result := GraphFromNode[T](temp)
-
-	// Set the parents of all atoms.
-	SetParents(result)
-
-	// Count the number of times each atom id appears in the
-	nodeCounter := make(map[string]nodeCount[T])
-	Traverse[Graph[T]](result, func(node Graph[T]) {
-		id := node.GetID()
-		if tmp, exists := nodeCounter[id]; !exists {
-			nodeCounter[id] = nodeCount[T]{
-				count: 1,
-				node:  node,
-			}
-		} else {
-			tmp.count += 1
-		}
-	})
 
-	// Replace each duplicate atom with a single atom that has all the parents of the duplicates.
-	for nodeID, data := range nodeCounter {
-		if data.count < 2 {
-			continue
-		}
-
-		// We will replace all duplicate instances with a single instance.
-		replacementChild := data.node
-
-		// Get all the parents of each instance of this atom.
-		parents := FindAllParentsByID[Graph[T]](result, nodeID)
-
-		// Set the complete list of parents on our new only-child atom.
-		replacementChild.SetParents(parents)
-
-		// Replace each instance with our replacement.
-		for _, parent := range parents {
-			parentChildren := parent.GetChildren()
-			for childIndex, parentChild := range parentChildren {
-				if parentChild.GetID() == nodeID {
-					parentChildren[childIndex] = replacementChild
-				}
-			}
-		}
-	}
+	// A node with multiple parents is written out once per parent, so build each ID only once and share it between
+	// all of its parents.  Nodes that only share an ID, with different meta or children, are kept apart so that
+	// Validate reports the duplicate ID.
+	return graphFromNode[T](temp, make(map[string]decodedGraph[T])), nil
+}
 
-	return result, nil
+type decodedGraph[T any] struct {
+	json *NodeJSON[T]
+	node Graph[T]
 }
 
 func GraphFromNode[T any](n *NodeJSON[T]) Graph[T] {
@@ -161,3 +171,52 @@ func GraphFromNode[T any](n *NodeJSON[T]) Graph[T] {
 	}
 	return result
 }
+
+func graphFromNode[T any](n *NodeJSON[T], nodes map[string]decodedGraph[T]) Graph[T] {
+	existing, ok := nodes[n.ID]
+	if ok && sameNodeJSON(existing.json, n) {
+		return existing.node
+	}
+	result := &graph[T]{
+		ID:       n.ID,
+		Meta:     n.Meta,
+		Children: []Graph[T]{},
+		parents:  []Graph[T]{},
+	}
+	if n.ID != "" && !ok {
+		nodes[n.ID] = decodedGraph[T]{
+			json: n,
+			node: result,
+		}
+	}
+	for _, child := range n.Children {
+		result.AddChild(graphFromNode[T](child, nodes))
+	}
+	return result
+}
+
+// Check whether two decoded nodes have the same ID, meta and children.
+func sameNodeJSON[T any](a, b *NodeJSON[T]) bool {
+	if a.ID != b.ID || len(a.Children) != len(b.Children) || !sameMeta(a.Meta, b.Meta) {
+		return false
+	}
+	for i := range a.Children {
+		if !sameNodeJSON(a.Children[i], b.Children[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Raw JSON meta is compared without whitespace, which differs with the depth of the copies in indented JSON.
+func sameMeta[T any](a, b T) bool {
+	rawA, okA := any(a).(json.RawMessage)
+	rawB, okB := any(b).(json.RawMessage)
+	if okA && okB {
+		var compactA, compactB bytes.Buffer
+		if json.Compact(&compactA, rawA) == nil && json.Compact(&compactB, rawB) == nil {
+			return bytes.Equal(compactA.Bytes(), compactB.Bytes())
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
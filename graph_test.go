@@ -1,6 +1,7 @@
 package girraph
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -98,6 +99,85 @@ func TestGraph_AddChild_AddsParent(t *testing.T) {
 	assert.Len(t, nodeC.GetParents(), 2)
 }
 
+func TestGraph_SetChildren_RemovesParents(t *testing.T) {
+	graph := getGraphFixture()
+	nodeB := graph.GetChildren()[0]
+	nodeC := MakeGraph[CustomGraph]().SetID("C")
+
+	graph.SetChildren([]Graph[CustomGraph]{nodeC})
+
+	assert.Empty(t, nodeB.GetParents())
+	assert.Equal(t, []Graph[CustomGraph]{graph}, nodeC.GetParents())
+}
+
+func TestGraphFromJSON_SharesNodes(t *testing.T) {
+	input, err := getGraphFixture().JSON()
+	require.Nil(t, err)
+
+	graph, err := GraphFromJSON[*customGraph](input)
+	require.Nil(t, err)
+
+	// D is written out under both B and C, but is read back as a single node with both parents.
+	nodeB, nodeC := graph.GetChildren()[0], graph.GetChildren()[1]
+	nodeD := nodeB.GetChildren()[0]
+	assert.Same(t, nodeD, nodeC.GetChildren()[0])
+	assert.Equal(t, []Graph[*customGraph]{nodeB, nodeC}, nodeD.GetParents())
+	assert.Equal(t, []Graph[*customGraph]{graph}, nodeB.GetParents())
+	assert.Equal(t, []Graph[*customGraph]{graph}, nodeC.GetParents())
+	assert.Empty(t, graph.GetParents())
+}
+
+func TestGraphFromJSON_EmptyIDs(t *testing.T) {
+	input := []byte(`{"ID":"A","Children":[{"ID":"","Meta":{"Name":"one"}},{"ID":"","Meta":{"Name":"two"}}]}`)
+
+	graph, err := GraphFromJSON[*customGraph](input)
+	require.Nil(t, err)
+
+	// Nodes without an ID can't be told apart, so they are never shared.
+	children := graph.GetChildren()
+	require.Len(t, children, 2)
+	assert.Equal(t, "one", children[0].GetMeta().GetName())
+	assert.Equal(t, "two", children[1].GetMeta().GetName())
+}
+
+func TestGraphFromJSON_DuplicateIDs(t *testing.T) {
+	input := []byte(`{"ID":"A","Meta":1,"Children":[
+		{"ID":"X","Meta":5,"Children":[]},
+		{"ID":"X","Meta":7,"Children":[{"ID":"Y","Meta":9,"Children":[]}]}
+	]}`)
+
+	graph, err := GraphFromJSON[int](input)
+	require.Nil(t, err)
+
+	// Nodes that only share an ID are kept apart, rather than the first one replacing the others.
+	children := graph.GetChildren()
+	require.Len(t, children, 2)
+	assert.Equal(t, 5, children[0].GetMeta())
+	assert.Empty(t, children[0].GetChildren())
+	assert.Equal(t, 7, children[1].GetMeta())
+	assert.Len(t, children[1].GetChildren(), 1)
+
+	problems := Validate(graph)
+	require.Len(t, problems, 1)
+	assert.Equal(t, ProblemDuplicateID, problems[0].Type)
+}
+
+func TestGraphFromJSON_RawMeta(t *testing.T) {
+	input := []byte(`{"ID":"A","Children":[
+		{"ID":"B","Children":[{"ID":"D","Meta":{"name": "deploy"}}]},
+		{"ID":"C","Children":[{"ID":"D","Meta":{
+			"name": "deploy"
+		}}]}
+	]}`)
+
+	graph, err := GraphFromJSON[json.RawMessage](input)
+	require.Nil(t, err)
+
+	// The copies of D are only formatted differently.
+	nodeD := graph.GetChildren()[0].GetChildren()[0]
+	assert.Same(t, nodeD, graph.GetChildren()[1].GetChildren()[0])
+}
+
 func getGraphFixture() Graph[CustomGraph] {
 	nodeA := MakeGraph[CustomGraph]().SetID("A").SetMeta(&customGraph{})
 	nodeA.GetMeta().SetName("node A")
@@ -0,0 +1,182 @@
+package girraph
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"sort"
+)
+
+// A SHA-256 digest of a node's meta and the digests of its children, in order.  IDs are not hashed, so identical
+// subtrees have the same digest even when their IDs differ.
+type Digest [sha256.Size]byte
+
+func (d Digest) String() string {
+	return hex.EncodeToString(d[:])
+}
+
+func (d Digest) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Digest) UnmarshalText(text []byte) error {
+	decoded, err := hex.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	if len(decoded) != len(d) {
+		return fmt.Errorf("digest must be %d bytes, got %d", len(d), len(decoded))
+	}
+	copy(d[:], decoded)
+	return nil
+}
+
+// Meta can implement CanonicalEncoder to control the bytes that are hashed for it.  By default, meta is hashed as JSON.
+type CanonicalEncoder interface {
+	CanonicalEncoding() ([]byte, error)
+}
+
+// Compute the digest of a node.  Digests are cached on the node and cleared when it or one of its descendants is
+// mutated; see Invalidate for meta that is changed in place.
+func Hash[T MetaNode[T, M], M any](node T) (Digest, error) {
+	h := &hasher[T, M]{
+		digests: make(map[any]Digest),
+		onPath:  make(map[any]bool),
+		sum:     sha256.New(),
+	}
+	return h.hash(node)
+}
+
+type hasher[T MetaNode[T, M], M any] struct {
+	digests map[any]Digest
+	onPath  map[any]bool
+	sum     hash.Hash
+}
+
+func (h *hasher[T, M]) hash(node T) (Digest, error) {
+	cache, cacheable := any(node).(cacheable)
+	if cacheable && cache.getCache().hasDigest {
+		return cache.getCache().digest, nil
+	}
+	if digest, ok := h.digests[node]; ok {
+		return digest, nil
+	}
+	if h.onPath[node] {
+		return Digest{}, fmt.Errorf("%w: node %q is its own descendant", ErrCycle, node.GetID())
+	}
+	h.onPath[node] = true
+	defer delete(h.onPath, node)
+
+	children := node.GetChildren()
+	childDigests := make([]Digest, len(children))
+	for i, child := range children {
+		digest, err := h.hash(child)
+		if err != nil {
+			return Digest{}, err
+		}
+		childDigests[i] = digest
+	}
+
+	meta, err := canonicalEncoding(node.GetMeta())
+	if err != nil {
+		return Digest{}, fmt.Errorf("failed to encode meta of node %q: %w", node.GetID(), err)
+	}
+
+	// Length-prefix each part so that different shapes can't produce the same input.
+	var length [8]byte
+	h.sum.Reset()
+	binary.BigEndian.PutUint64(length[:], uint64(len(meta)))
+	h.sum.Write(length[:])
+	h.sum.Write(meta)
+	binary.BigEndian.PutUint64(length[:], uint64(len(childDigests)))
+	h.sum.Write(length[:])
+	for _, digest := range childDigests {
+		h.sum.Write(digest[:])
+	}
+	var result Digest
+	h.sum.Sum(result[:0])
+
+	h.digests[node] = result
+	if cacheable {
+		cache.getCache().digest = result
+		cache.getCache().hasDigest = true
+	}
+	return result, nil
+}
+
+func canonicalEncoding(meta any) ([]byte, error) {
+	if encoder, ok := meta.(CanonicalEncoder); ok {
+		return encoder.CanonicalEncoding()
+	}
+	return json.Marshal(meta)
+}
+
+// An index of nodes by digest, used to find identical subtrees within and across graphs.
+type HashIndex[T Node[T]] struct {
+	hash  func(T) (Digest, error)
+	nodes map[Digest][]T
+	added map[any]bool
+}
+
+func NewHashIndex[T MetaNode[T, M], M any]() *HashIndex[T] {
+	return &HashIndex[T]{
+		hash:  Hash[T, M],
+		nodes: make(map[Digest][]T),
+		added: make(map[any]bool),
+	}
+}
+
+// Index the root and all of its descendants.
+func (i *HashIndex[T]) Add(root T) error {
+	if i.added[root] {
+		return nil
+	}
+	digest, err := i.hash(root)
+	if err != nil {
+		return err
+	}
+	i.added[root] = true
+	i.nodes[digest] = append(i.nodes[digest], root)
+	for _, child := range root.GetChildren() {
+		if err := i.Add(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get the indexed nodes with the provided digest.
+func (i *HashIndex[T]) Find(digest Digest) []T {
+	return i.nodes[digest]
+}
+
+// Get the indexed nodes with the same digest as the provided node.
+func (i *HashIndex[T]) FindIdentical(node T) ([]T, error) {
+	digest, err := i.hash(node)
+	if err != nil {
+		return nil, err
+	}
+	return i.nodes[digest], nil
+}
+
+// Get each group of distinct nodes that have identical subtrees, ordered by digest.
+func (i *HashIndex[T]) Duplicates() [][]T {
+	var digests []Digest
+	for digest, nodes := range i.nodes {
+		if len(nodes) > 1 {
+			digests = append(digests, digest)
+		}
+	}
+	sort.Slice(digests, func(a, b int) bool {
+		return bytes.Compare(digests[a][:], digests[b][:]) < 0
+	})
+	result := make([][]T, len(digests))
+	for n, digest := range digests {
+		result[n] = i.nodes[digest]
+	}
+	return result
+}
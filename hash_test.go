@@ -0,0 +1,113 @@
+package girraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHash_IgnoresIDs(t *testing.T) {
+	first, err := Hash(getGraphFixture())
+	require.Nil(t, err)
+
+	graph := getGraphFixture()
+	Traverse(graph, func(node Graph[CustomGraph]) {
+		node.SetID(node.GetID() + "'")
+	})
+	second, err := Hash(graph)
+	require.Nil(t, err)
+
+	assert.Equal(t, first, second)
+}
+
+func TestHash_Tree(t *testing.T) {
+	first, err := Hash(getTreeFixture())
+	require.Nil(t, err)
+
+	second, err := Hash(getTreeFixture())
+	require.Nil(t, err)
+
+	assert.Equal(t, first, second)
+}
+
+func TestHash_InvalidatedOnMutation(t *testing.T) {
+	graph := getGraphFixture()
+	before, err := Hash(graph)
+	require.Nil(t, err)
+
+	nodeD := graph.GetChildren()[0].GetChildren()[0]
+	nodeD.SetMeta(&customGraph{Name: "changed"})
+	after, err := Hash(graph)
+	require.Nil(t, err)
+	assert.NotEqual(t, before, after)
+
+	nodeD.GetMeta().SetName("node D")
+	stale, err := Hash(graph)
+	require.Nil(t, err)
+	assert.Equal(t, after, stale)
+
+	Invalidate(nodeD)
+	restored, err := Hash(graph)
+	require.Nil(t, err)
+	assert.Equal(t, before, restored)
+}
+
+func TestHash_ChildOrder(t *testing.T) {
+	graph := getGraphFixture()
+	before, err := Hash(graph)
+	require.Nil(t, err)
+
+	children := graph.GetChildren()
+	graph.SetChildren([]Graph[CustomGraph]{children[1], children[0]})
+	after, err := Hash(graph)
+	require.Nil(t, err)
+
+	assert.NotEqual(t, before, after)
+}
+
+func TestHash_Cycle(t *testing.T) {
+	graph := getGraphFixture()
+	graph.GetChildren()[0].AddChild(graph)
+
+	_, err := Hash(graph)
+	assert.True(t, errors.Is(err, ErrCycle))
+}
+
+func TestDigest_JSON(t *testing.T) {
+	digest, err := Hash(getGraphFixture())
+	require.Nil(t, err)
+
+	text, err := digest.MarshalText()
+	require.Nil(t, err)
+
+	var result Digest
+	require.Nil(t, result.UnmarshalText(text))
+	assert.Equal(t, digest, result)
+}
+
+func TestHashIndex(t *testing.T) {
+	first := getGraphFixture()
+	second := getGraphFixture()
+	second.GetChildren()[1].SetMeta(&customGraph{Name: "changed"})
+
+	index := NewHashIndex[Graph[CustomGraph]]()
+	require.Nil(t, index.Add(first))
+	require.Nil(t, index.Add(second))
+
+	// Node B is unchanged, so it is found in both graphs.
+	found, err := index.FindIdentical(first.GetChildren()[0])
+	require.Nil(t, err)
+	require.Len(t, found, 2)
+	assert.Same(t, first.GetChildren()[0], found[0])
+	assert.Same(t, second.GetChildren()[0], found[1])
+
+	// The roots differ below node C.
+	found, err = index.FindIdentical(first)
+	require.Nil(t, err)
+	assert.Len(t, found, 1)
+
+	// Node D is shared within each graph, so it is indexed once per graph.
+	assert.Len(t, index.Duplicates(), 2)
+}
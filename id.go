@@ -0,0 +1,195 @@
+package girraph
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Generates the IDs of new nodes.  The content is whatever was passed to WithIDContent when the node was made, or nil.
+type IDGenerator interface {
+	NewID(content any) string
+}
+
+type IDGeneratorFunc func(content any) string
+
+func (f IDGeneratorFunc) NewID(content any) string {
+	return f(content)
+}
+
+var (
+	defaultIDGenerator   IDGenerator = UUIDGenerator{}
+	defaultIDGeneratorMu sync.RWMutex
+)
+
+// Set the generator used by MakeGraph, MakeTree and MakeTreeNode when no WithIDGenerator option is provided.
+func SetDefaultIDGenerator(generator IDGenerator) {
+	defaultIDGeneratorMu.Lock()
+	defer defaultIDGeneratorMu.Unlock()
+	defaultIDGenerator = generator
+}
+
+func DefaultIDGenerator() IDGenerator {
+	defaultIDGeneratorMu.RLock()
+	defer defaultIDGeneratorMu.RUnlock()
+	return defaultIDGenerator
+}
+
+// Options for the node constructors.
+type Option func(*options)
+
+type options struct {
+	ids     IDGenerator
+	content any
+}
+
+// Generate the node ID with the provided generator instead of the package default.
+func WithIDGenerator(generator IDGenerator) Option {
+	return func(o *options) {
+		o.ids = generator
+	}
+}
+
+// Pass content to the ID generator, e.g. the node meta for a ContentHashGenerator.
+func WithIDContent(content any) Option {
+	return func(o *options) {
+		o.content = content
+	}
+}
+
+func newID(opts []Option) string {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.ids == nil {
+		o.ids = DefaultIDGenerator()
+	}
+	return o.ids.NewID(o.content)
+}
+
+// Generates random version 4 UUIDs.  This is the package default.
+type UUIDGenerator struct{}
+
+func (UUIDGenerator) NewID(any) string {
+	return uuid.New().String()
+}
+
+// Generates sequential IDs, "1", "2", "3" and so on, after the optional prefix.  Useful for deterministic tests.
+type SequentialGenerator struct {
+	prefix string
+	last   uint64
+}
+
+func NewSequentialGenerator(prefix string) *SequentialGenerator {
+	return &SequentialGenerator{
+		prefix: prefix,
+	}
+}
+
+func (g *SequentialGenerator) NewID(any) string {
+	return fmt.Sprintf("%s%d", g.prefix, atomic.AddUint64(&g.last, 1))
+}
+
+// Generates ULIDs: 26 character IDs made of a millisecond timestamp followed by 80 random bits, so that IDs sort in
+// the order they were made.  IDs made within the same millisecond increment the random bits to keep that order.  The
+// zero value is ready to use.
+type ULIDGenerator struct {
+	now     func() time.Time
+	entropy io.Reader
+	mu      sync.Mutex
+	last    uint64
+	random  [10]byte
+}
+
+func NewULIDGenerator() *ULIDGenerator {
+	return &ULIDGenerator{
+		now:     time.Now,
+		entropy: rand.Reader,
+	}
+}
+
+func (g *ULIDGenerator) NewID(any) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now, entropy := g.now, g.entropy
+	if now == nil {
+		now = time.Now
+	}
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+
+	ms := uint64(now().UnixMilli())
+	if ms > g.last {
+		g.last = ms
+		if _, err := io.ReadFull(entropy, g.random[:]); err != nil {
+			panic(err)
+		}
+	} else if incrementBytes(g.random[:]) {
+		// The random bits overflowed, so move on to the next millisecond.
+		g.last++
+	}
+
+	var id [16]byte
+	binary.BigEndian.PutUint16(id[0:2], uint16(g.last>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(g.last))
+	copy(id[6:], g.random[:])
+	return encodeCrockford(id)
+}
+
+// Increment a big-endian number in place, returning true if it overflowed.
+func incrementBytes(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// Encode 128 bits as 26 Crockford base32 characters, most significant first.
+func encodeCrockford(id [16]byte) string {
+	hi := binary.BigEndian.Uint64(id[0:8])
+	lo := binary.BigEndian.Uint64(id[8:16])
+	result := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		result[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(result)
+}
+
+// Generates IDs from a SHA-256 digest of the JSON encoding of the content passed with WithIDContent, so that nodes made
+// with equal content get equal IDs.  The fallback generator, or the package default, is used when there is no content.
+type ContentHashGenerator struct {
+	Fallback IDGenerator
+}
+
+func (g ContentHashGenerator) NewID(content any) string {
+	if content != nil {
+		data, err := json.Marshal(content)
+		if err == nil {
+			sum := sha256.Sum256(data)
+			return hex.EncodeToString(sum[:16])
+		}
+	}
+	if g.Fallback != nil {
+		return g.Fallback.NewID(content)
+	}
+	return DefaultIDGenerator().NewID(content)
+}
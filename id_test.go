@@ -0,0 +1,74 @@
+package girraph
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSequentialGenerator(t *testing.T) {
+	ids := NewSequentialGenerator("node-")
+	graph := MakeGraph[CustomGraph](WithIDGenerator(ids))
+	tree := MakeTree[CustomTree](WithIDGenerator(ids))
+	assert.Equal(t, "node-1", graph.GetID())
+	assert.Equal(t, "node-2", tree.GetID())
+}
+
+func TestSetDefaultIDGenerator(t *testing.T) {
+	defer SetDefaultIDGenerator(DefaultIDGenerator())
+	SetDefaultIDGenerator(NewSequentialGenerator(""))
+
+	assert.Equal(t, "1", MakeGraph[CustomGraph]().GetID())
+	assert.Equal(t, "2", MakeTreeNode[CustomTree]().GetID())
+}
+
+func TestULIDGenerator(t *testing.T) {
+	now := time.UnixMilli(1469918176385)
+	ids := NewULIDGenerator()
+	ids.now = func() time.Time { return now }
+	ids.entropy = bytes.NewReader(make([]byte, 20))
+
+	first := ids.NewID(nil)
+	second := ids.NewID(nil)
+	now = now.Add(time.Millisecond)
+	third := ids.NewID(nil)
+
+	assert.Equal(t, "01ARYZ6S410000000000000000", first)
+	assert.Equal(t, "01ARYZ6S410000000000000001", second)
+	assert.Equal(t, "01ARYZ6S420000000000000000", third)
+}
+
+func TestULIDGenerator_Sortable(t *testing.T) {
+	ids := NewULIDGenerator()
+	var generated []string
+	for i := 0; i < 100; i++ {
+		generated = append(generated, ids.NewID(nil))
+	}
+	assert.True(t, sort.StringsAreSorted(generated))
+	assert.Len(t, generated[0], 26)
+}
+
+func TestULIDGenerator_ZeroValue(t *testing.T) {
+	ids := &ULIDGenerator{}
+	first := ids.NewID(nil)
+	second := ids.NewID(nil)
+	assert.Len(t, first, 26)
+	assert.Less(t, first, second)
+}
+
+func TestContentHashGenerator(t *testing.T) {
+	ids := ContentHashGenerator{Fallback: NewSequentialGenerator("seq-")}
+
+	first := MakeGraph[*customGraph](WithIDGenerator(ids), WithIDContent(&customGraph{Name: "A"}))
+	second := MakeGraph[*customGraph](WithIDGenerator(ids), WithIDContent(&customGraph{Name: "A"}))
+	third := MakeGraph[*customGraph](WithIDGenerator(ids), WithIDContent(&customGraph{Name: "B"}))
+	require.Len(t, first.GetID(), 32)
+	assert.Equal(t, first.GetID(), second.GetID())
+	assert.NotEqual(t, first.GetID(), third.GetID())
+
+	assert.Equal(t, "seq-1", ids.NewID(nil))
+}
@@ -0,0 +1,399 @@
+package girraph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNoHistory = errors.New("no history")
+	// The nodes were changed in a way the journal did not record, so a step can no longer be undone or redone.
+	ErrJournalConflict = errors.New("nodes no longer match the journal")
+)
+
+type JournalOptions[T any, M any] struct {
+	// The number of undo steps to keep; older steps are dropped.  Zero keeps every step.
+	Limit int
+	// Makes a node when resuming a journal that refers to nodes that are no longer below the root, e.g. nodes that
+	// were removed and could be restored by undo.  Its ID, meta and children are set from the journal.
+	NewNode func() T
+	// Decodes meta when resuming a journal.  By default meta is decoded with json.Unmarshal.
+	DecodeMeta func(data json.RawMessage) (M, error)
+}
+
+// Records the changes made to the nodes below a root so that they can be undone and redone.  Each delivery of events
+// to the journal's observer is one undo step, so a transaction or a Group is undone as a whole.  Making a change after
+// an undo discards the steps that could have been redone.
+type Journal[T MetaNode[T, M], M any] struct {
+	root      T
+	options   JournalOptions[T, M]
+	undo      [][]Event[T]
+	redo      [][]Event[T]
+	replaying bool
+	cancel    func()
+}
+
+// Start recording the changes made to root and its descendants.
+func NewJournal[T MetaNode[T, M], M any](root T, options JournalOptions[T, M]) (*Journal[T, M], error) {
+	j := &Journal[T, M]{
+		root:    root,
+		options: options,
+	}
+	cancel, err := Observe(root, j.record)
+	if err != nil {
+		return nil, err
+	}
+	j.cancel = cancel
+	return j, nil
+}
+
+// Stop recording changes.
+func (j *Journal[T, M]) Close() {
+	j.cancel()
+}
+
+func (j *Journal[T, M]) record(events []Event[T]) {
+	if j.replaying {
+		return
+	}
+	var step []Event[T]
+	for _, event := range events {
+		if event.Type != NodeAdded && event.Type != NodeRemoved {
+			step = append(step, event)
+		}
+	}
+	if len(step) == 0 {
+		return
+	}
+	j.push(step)
+	j.redo = nil
+}
+
+func (j *Journal[T, M]) push(step []Event[T]) {
+	j.undo = append(j.undo, step)
+	if j.options.Limit > 0 && len(j.undo) > j.options.Limit {
+		j.undo = append([][]Event[T]{}, j.undo[len(j.undo)-j.options.Limit:]...)
+	}
+}
+
+// Make the changes in fn a single undo step.
+func (j *Journal[T, M]) Group(fn func()) {
+	Batch(j.root, fn)
+}
+
+func (j *Journal[T, M]) CanUndo() bool {
+	return len(j.undo) > 0
+}
+
+func (j *Journal[T, M]) CanRedo() bool {
+	return len(j.redo) > 0
+}
+
+// Undo the last step.  If the nodes no longer match the step, nothing is changed and ErrJournalConflict is returned.
+func (j *Journal[T, M]) Undo() error {
+	if len(j.undo) == 0 {
+		return ErrNoHistory
+	}
+	step := j.undo[len(j.undo)-1]
+	if err := j.replay(step, true); err != nil {
+		return err
+	}
+	j.undo = j.undo[:len(j.undo)-1]
+	j.redo = append(j.redo, step)
+	return nil
+}
+
+// Redo the last undone step.
+func (j *Journal[T, M]) Redo() error {
+	if len(j.redo) == 0 {
+		return ErrNoHistory
+	}
+	step := j.redo[len(j.redo)-1]
+	if err := j.replay(step, false); err != nil {
+		return err
+	}
+	j.redo = j.redo[:len(j.redo)-1]
+	j.push(step)
+	return nil
+}
+
+// Stage the inverse of a step, or the step itself, in a transaction and commit it.
+func (j *Journal[T, M]) replay(step []Event[T], undo bool) error {
+	tx := Begin[T, M](j.root)
+	for i := range step {
+		event := step[i]
+		if undo {
+			event = step[len(step)-1-i]
+		}
+		insert := event.Type == EdgeAdded
+		switch event.Type {
+		case EdgeAdded, EdgeRemoved:
+			if undo {
+				insert = !insert
+			}
+			children := tx.GetChildren(event.Parent)
+			if insert {
+				if event.Index > len(children) {
+					return fmt.Errorf("%w: %q has no child at index %d", ErrJournalConflict, tx.GetID(event.Parent), event.Index)
+				}
+				children = append(children[:event.Index], append([]T{event.Node}, children[event.Index:]...)...)
+			} else {
+				if event.Index >= len(children) || any(children[event.Index]) != any(event.Node) {
+					return fmt.Errorf("%w: %q is not the child of %q at index %d", ErrJournalConflict, tx.GetID(event.Node), tx.GetID(event.Parent), event.Index)
+				}
+				children = append(children[:event.Index], children[event.Index+1:]...)
+			}
+			tx.SetChildren(event.Parent, children)
+		case MetaChanged:
+			meta := event.NewMeta
+			if undo {
+				meta = event.OldMeta
+			}
+			m, _ := meta.(M)
+			tx.SetMeta(event.Node, m)
+		case IDChanged:
+			id := event.NewID
+			if undo {
+				id = event.OldID
+			}
+			tx.SetID(event.Node, id)
+		}
+	}
+	j.replaying = true
+	defer func() {
+		j.replaying = false
+	}()
+	return tx.Commit()
+}
+
+type journalJSON struct {
+	Nodes []journalNodeJSON
+	Undo  [][]journalEventJSON
+	Redo  [][]journalEventJSON
+}
+
+// A node referred to by the journal.  Nodes below the root are found by ID when the journal is resumed; detached
+// nodes are rebuilt from their meta and children.
+type journalNodeJSON struct {
+	Ref      int
+	ID       string
+	Detached bool            `json:",omitempty"`
+	Meta     json.RawMessage `json:",omitempty"`
+	Children []int           `json:",omitempty"`
+}
+
+type journalEventJSON struct {
+	Type    EventType
+	Node    int
+	Parent  int             `json:",omitempty"`
+	Index   int             `json:",omitempty"`
+	OldID   string          `json:",omitempty"`
+	NewID   string          `json:",omitempty"`
+	OldMeta json.RawMessage `json:",omitempty"`
+	NewMeta json.RawMessage `json:",omitempty"`
+}
+
+// Encode the journal so that it can be resumed with ResumeJournal.  The nodes below the root are referred to by ID, so
+// their IDs must be unique.
+func (j *Journal[T, M]) MarshalJSON() ([]byte, error) {
+	reachable := make(map[any]bool)
+	ids := make(map[string]int)
+	WalkOnce[T](j.root, VisitorFuncs[T]{
+		OnEnter: func(node T, depth int) VisitAction {
+			reachable[node] = true
+			ids[node.GetID()]++
+			return Continue
+		},
+	})
+
+	refs := make(map[any]int)
+	var nodes []T
+	ref := func(node T) int {
+		if r, ok := refs[node]; ok {
+			return r
+		}
+		nodes = append(nodes, node)
+		refs[node] = len(nodes)
+		return len(nodes)
+	}
+	encodeSteps := func(steps [][]Event[T]) ([][]journalEventJSON, error) {
+		result := [][]journalEventJSON{}
+		for _, step := range steps {
+			var encoded []journalEventJSON
+			for _, event := range step {
+				e := journalEventJSON{
+					Type:  event.Type,
+					Node:  ref(event.Node),
+					Index: event.Index,
+					OldID: event.OldID,
+					NewID: event.NewID,
+				}
+				switch event.Type {
+				case EdgeAdded, EdgeRemoved:
+					e.Parent = ref(event.Parent)
+				case MetaChanged:
+					var err error
+					if e.OldMeta, err = json.Marshal(event.OldMeta); err != nil {
+						return nil, err
+					}
+					if e.NewMeta, err = json.Marshal(event.NewMeta); err != nil {
+						return nil, err
+					}
+				}
+				encoded = append(encoded, e)
+			}
+			result = append(result, encoded)
+		}
+		return result, nil
+	}
+
+	var result journalJSON
+	var err error
+	if result.Undo, err = encodeSteps(j.undo); err != nil {
+		return nil, err
+	}
+	if result.Redo, err = encodeSteps(j.redo); err != nil {
+		return nil, err
+	}
+
+	// Detached nodes are encoded with their descendants, which may not be referred to by any event.
+	for i := 0; i < len(nodes); i++ {
+		node := nodes[i]
+		encoded := journalNodeJSON{
+			Ref: i + 1,
+			ID:  node.GetID(),
+		}
+		if reachable[node] {
+			if ids[node.GetID()] > 1 {
+				return nil, fmt.Errorf("journal node %q: ID is not unique", node.GetID())
+			}
+		} else {
+			encoded.Detached = true
+			if encoded.Meta, err = json.Marshal(node.GetMeta()); err != nil {
+				return nil, fmt.Errorf("journal node %q: %w", node.GetID(), err)
+			}
+			for _, child := range node.GetChildren() {
+				encoded.Children = append(encoded.Children, ref(child))
+			}
+		}
+		result.Nodes = append(result.Nodes, encoded)
+	}
+	return json.Marshal(result)
+}
+
+// Resume recording the changes to root with the history encoded by Journal.MarshalJSON.  Root must match the nodes
+// that the journal was encoded from.
+func ResumeJournal[T MetaNode[T, M], M any](root T, data []byte, options JournalOptions[T, M]) (*Journal[T, M], error) {
+	var encoded journalJSON
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, err
+	}
+	decodeMeta := options.DecodeMeta
+	if decodeMeta == nil {
+		decodeMeta = func(data json.RawMessage) (M, error) {
+			var meta M
+			err := json.Unmarshal(data, &meta)
+			return meta, err
+		}
+	}
+
+	byID := make(map[string]T)
+	WalkOnce[T](root, VisitorFuncs[T]{
+		OnEnter: func(node T, depth int) VisitAction {
+			if _, ok := byID[node.GetID()]; !ok {
+				byID[node.GetID()] = node
+			}
+			return Continue
+		},
+	})
+	nodes := make(map[int]T)
+	for _, n := range encoded.Nodes {
+		if !n.Detached {
+			node, ok := byID[n.ID]
+			if !ok {
+				return nil, fmt.Errorf("journal node %q: %w", n.ID, ErrNotFound)
+			}
+			nodes[n.Ref] = node
+			continue
+		}
+		if options.NewNode == nil {
+			return nil, fmt.Errorf("journal node %q is detached and NewNode is not set", n.ID)
+		}
+		meta, err := decodeMeta(n.Meta)
+		if err != nil {
+			return nil, fmt.Errorf("journal node %q: %w", n.ID, err)
+		}
+		nodes[n.Ref] = options.NewNode().SetID(n.ID).SetMeta(meta)
+	}
+	resolve := func(ref int) (T, error) {
+		node, ok := nodes[ref]
+		if !ok {
+			return node, fmt.Errorf("journal node %d: %w", ref, ErrNotFound)
+		}
+		return node, nil
+	}
+	for _, n := range encoded.Nodes {
+		if !n.Detached {
+			continue
+		}
+		var children []T
+		for _, ref := range n.Children {
+			child, err := resolve(ref)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, child)
+		}
+		nodes[n.Ref].SetChildren(children)
+	}
+
+	decodeSteps := func(steps [][]journalEventJSON) ([][]Event[T], error) {
+		var result [][]Event[T]
+		for _, step := range steps {
+			var decoded []Event[T]
+			for _, e := range step {
+				event := Event[T]{
+					Type:  e.Type,
+					Index: e.Index,
+					OldID: e.OldID,
+					NewID: e.NewID,
+				}
+				var err error
+				if event.Node, err = resolve(e.Node); err != nil {
+					return nil, err
+				}
+				switch e.Type {
+				case EdgeAdded, EdgeRemoved:
+					if event.Parent, err = resolve(e.Parent); err != nil {
+						return nil, err
+					}
+				case MetaChanged:
+					if event.OldMeta, err = decodeMeta(e.OldMeta); err != nil {
+						return nil, err
+					}
+					if event.NewMeta, err = decodeMeta(e.NewMeta); err != nil {
+						return nil, err
+					}
+				}
+				decoded = append(decoded, event)
+			}
+			result = append(result, decoded)
+		}
+		return result, nil
+	}
+
+	j, err := NewJournal(root, options)
+	if err != nil {
+		return nil, err
+	}
+	if j.undo, err = decodeSteps(encoded.Undo); err != nil {
+		j.Close()
+		return nil, err
+	}
+	if j.redo, err = decodeSteps(encoded.Redo); err != nil {
+		j.Close()
+		return nil, err
+	}
+	return j, nil
+}
@@ -0,0 +1,145 @@
+package girraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getStringGraphFixture() Graph[string] {
+	a := MakeGraph[string]().SetID("A").SetMeta("a")
+	b := MakeGraph[string]().SetID("B").SetMeta("b")
+	c := MakeGraph[string]().SetID("C").SetMeta("c")
+	d := MakeGraph[string]().SetID("D").SetMeta("d")
+	b.AddChild(d)
+	c.AddChild(d)
+	return a.AddChild(b).AddChild(c)
+}
+
+func TestJournal_UndoRedo(t *testing.T) {
+	root := getStringGraphFixture()
+	journal, err := NewJournal(root, JournalOptions[Graph[string], string]{})
+	require.Nil(t, err)
+	b := FindNodesByID(root, "B")[0]
+	c := FindNodesByID(root, "C")[0]
+	d := FindNodesByID(root, "D")[0]
+
+	b.SetMeta("changed")
+	c.SetChildren([]Graph[string]{MakeGraph[string]().SetID("E")})
+	d.SetID("D2")
+	assert.Equal(t, []string{"A", "B", "D2", "C", "E"}, getIDs(root))
+	assert.False(t, journal.CanRedo())
+
+	require.Nil(t, journal.Undo())
+	require.Nil(t, journal.Undo())
+	assert.Equal(t, []string{"A", "B", "D", "C", "D"}, getIDs(root))
+	assert.Equal(t, []Graph[string]{b, c}, d.GetParents())
+	require.Nil(t, journal.Undo())
+	assert.Equal(t, "b", b.GetMeta())
+	assert.False(t, journal.CanUndo())
+	assert.True(t, errors.Is(journal.Undo(), ErrNoHistory))
+
+	require.Nil(t, journal.Redo())
+	require.Nil(t, journal.Redo())
+	assert.Equal(t, "changed", b.GetMeta())
+	assert.Equal(t, []string{"A", "B", "D", "C", "E"}, getIDs(root))
+	assert.Empty(t, Validate(root))
+
+	root.SetMeta("new change")
+	assert.False(t, journal.CanRedo(), "a new change discards the redo steps")
+}
+
+func TestJournal_Group(t *testing.T) {
+	root := getTreeFixture()
+	journal, err := NewJournal(root, JournalOptions[Tree[CustomTree], CustomTree]{})
+	require.Nil(t, err)
+
+	journal.Group(func() {
+		root.AddChild(MakeTree[CustomTree]().SetID("E"))
+		root.GetChildren()[0].SetID("B2")
+	})
+	err = Update(root, func(tx *Transaction[Tree[CustomTree], CustomTree]) error {
+		tx.SetChildren(root, nil)
+		return nil
+	})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"A"}, getIDs(root))
+
+	require.Nil(t, journal.Undo())
+	assert.Equal(t, []string{"A", "B2", "C", "D", "E"}, getIDs(root))
+	require.Nil(t, journal.Undo())
+	assert.Equal(t, []string{"A", "B", "C", "D"}, getIDs(root))
+	assert.False(t, journal.CanUndo())
+	assert.Empty(t, Validate(root))
+}
+
+func TestJournal_Limit(t *testing.T) {
+	root := getStringGraphFixture()
+	journal, err := NewJournal(root, JournalOptions[Graph[string], string]{
+		Limit: 2,
+	})
+	require.Nil(t, err)
+
+	root.SetMeta("1")
+	root.SetMeta("2")
+	root.SetMeta("3")
+	require.Nil(t, journal.Undo())
+	require.Nil(t, journal.Undo())
+	assert.Equal(t, "1", root.GetMeta())
+	assert.True(t, errors.Is(journal.Undo(), ErrNoHistory))
+}
+
+func TestJournal_Conflict(t *testing.T) {
+	root := getStringGraphFixture()
+	journal, err := NewJournal(root, JournalOptions[Graph[string], string]{})
+	require.Nil(t, err)
+	b := FindNodesByID(root, "B")[0]
+
+	b.AddChild(MakeGraph[string]().SetID("E"))
+	journal.Close()
+	b.SetChildren([]Graph[string]{})
+
+	assert.True(t, errors.Is(journal.Undo(), ErrJournalConflict))
+	assert.True(t, journal.CanUndo())
+}
+
+func TestJournal_Resume(t *testing.T) {
+	root := getStringGraphFixture()
+	journal, err := NewJournal(root, JournalOptions[Graph[string], string]{})
+	require.Nil(t, err)
+
+	c := FindNodesByID(root, "C")[0]
+	c.SetChildren([]Graph[string]{MakeGraph[string]().SetID("E").SetMeta("e")})
+	root.SetChildren(root.GetChildren()[:1])
+	root.SetMeta("changed")
+	require.Nil(t, journal.Undo())
+
+	data, err := journal.MarshalJSON()
+	require.Nil(t, err)
+	journal.Close()
+
+	// Resume against a copy of the nodes, restoring the removed node C from the journal.
+	copied, err := GraphFromJSON[string]([]byte(`{"ID":"A","Meta":"a","Children":[{"ID":"B","Meta":"b","Children":[{"ID":"D","Meta":"d"}]}]}`))
+	require.Nil(t, err)
+	resumed, err := ResumeJournal(copied, data, JournalOptions[Graph[string], string]{
+		NewNode: func() Graph[string] {
+			return MakeGraph[string]()
+		},
+	})
+	require.Nil(t, err)
+
+	require.Nil(t, resumed.Redo())
+	assert.Equal(t, "changed", copied.GetMeta())
+	require.Nil(t, resumed.Undo())
+	require.Nil(t, resumed.Undo())
+	assert.Equal(t, []string{"A", "B", "D", "C", "E"}, getIDs(copied))
+	require.Nil(t, resumed.Undo())
+	assert.Equal(t, []string{"A", "B", "D", "C", "D"}, getIDs(copied))
+	assert.Len(t, FindNodesByID(copied, "D")[0].GetParents(), 2)
+	assert.Empty(t, Validate(copied))
+
+	_, err = ResumeJournal(copied, data, JournalOptions[Graph[string], string]{})
+	assert.NotNil(t, err, "detached nodes need NewNode")
+}
@@ -0,0 +1,90 @@
+package girraph
+
+import "fmt"
+
+// An error returned by a mapping function, tagged with the path to the node being mapped.
+type MapError struct {
+	Path NodePath
+	Err  error
+}
+
+func (e *MapError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Err)
+}
+
+func (e *MapError) Unwrap() error {
+	return e.Err
+}
+
+// Copy a graph, mapping each node's meta to a new type.  IDs and shape are kept: shared nodes are mapped once and stay
+// shared, and parent links are rebuilt.
+func MapGraph[T any, U any](root Graph[T], mapper func(T) (U, error)) (Graph[U], error) {
+	m := &graphMapping[T, U]{
+		mapper: mapper,
+		nodes:  make(map[Graph[T]]Graph[U]),
+	}
+	return m.mapNode(root, nil)
+}
+
+type graphMapping[T any, U any] struct {
+	mapper func(T) (U, error)
+	nodes  map[Graph[T]]Graph[U]
+}
+
+func (m *graphMapping[T, U]) mapNode(node Graph[T], path NodePath) (Graph[U], error) {
+	if mapped, ok := m.nodes[node]; ok {
+		return mapped, nil
+	}
+	path = append(path, node.GetID())
+	meta, err := m.mapper(node.GetMeta())
+	if err != nil {
+		return nil, &MapError{
+			Path: path,
+			Err:  err,
+		}
+	}
+	result := &graph[U]{
+		ID:       node.GetID(),
+		Meta:     meta,
+		Children: []Graph[U]{},
+		parents:  []Graph[U]{},
+	}
+	m.nodes[node] = result
+	for _, child := range node.GetChildren() {
+		mapped, err := m.mapNode(child, path)
+		if err != nil {
+			return nil, err
+		}
+		result.AddChild(mapped)
+	}
+	return result, nil
+}
+
+// Copy a tree, mapping each node's meta to a new type.  IDs and shape are kept, and parent links are rebuilt.
+func MapTree[T any, U any](root Tree[T], mapper func(T) (U, error)) (Tree[U], error) {
+	return mapTree(root, mapper, nil)
+}
+
+func mapTree[T any, U any](node Tree[T], mapper func(T) (U, error), path NodePath) (Tree[U], error) {
+	path = append(path, node.GetID())
+	meta, err := mapper(node.GetMeta())
+	if err != nil {
+		return nil, &MapError{
+			Path: path,
+			Err:  err,
+		}
+	}
+	result := &TreeNode[U]{
+		ID:       node.GetID(),
+		Meta:     meta,
+		Children: []Tree[U]{},
+	}
+	for _, child := range node.GetChildren() {
+		mapped, err := mapTree(child, mapper, path)
+		if err != nil {
+			return nil, err
+		}
+		result.AddChild(mapped)
+	}
+	return result, nil
+}
@@ -0,0 +1,72 @@
+package girraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nameLength struct {
+	Length int
+}
+
+func TestMapGraph(t *testing.T) {
+	graph := getGraphFixture()
+
+	result, err := MapGraph(graph, func(meta CustomGraph) (*nameLength, error) {
+		return &nameLength{Length: len(meta.GetName())}, nil
+	})
+	require.Nil(t, err)
+
+	assert.Empty(t, Validate(result))
+	assert.Equal(t, getIDs(graph), getIDs(result))
+	assert.Equal(t, 6, result.GetMeta().Length)
+
+	// Node D is shared by B and C.
+	nodes := FindNodesByID(result, "D")
+	require.Len(t, nodes, 2)
+	assert.Same(t, nodes[0], nodes[1])
+	assert.Len(t, nodes[0].GetParents(), 2)
+}
+
+func TestMapGraph_Error(t *testing.T) {
+	failure := errors.New("unsupported")
+	_, err := MapGraph(getGraphFixture(), func(meta CustomGraph) (int, error) {
+		if meta.GetName() == "node D" {
+			return 0, failure
+		}
+		return 0, nil
+	})
+
+	var mapErr *MapError
+	require.True(t, errors.As(err, &mapErr))
+	assert.Equal(t, NodePath{"A", "B", "D"}, mapErr.Path)
+	assert.True(t, errors.Is(err, failure))
+	assert.Equal(t, "/A/B/D: unsupported", err.Error())
+}
+
+func TestMapTree(t *testing.T) {
+	tree := getTreeFixture()
+
+	result, err := MapTree(tree, func(meta CustomTree) (string, error) {
+		return meta.GetName(), nil
+	})
+	require.Nil(t, err)
+
+	assert.Empty(t, Validate(result))
+	assert.Equal(t, getIDs(tree), getIDs(result))
+	assert.Equal(t, "node D", FindNodesByID(result, "D")[0].GetMeta())
+	assert.Equal(t, "C", FindNodesByID(result, "D")[0].GetParent().GetID())
+}
+
+func TestMapTree_Error(t *testing.T) {
+	_, err := MapTree(getTreeFixture(), func(meta CustomTree) (string, error) {
+		if meta.GetName() == "node D" {
+			return "", errors.New("unsupported")
+		}
+		return "", nil
+	})
+	assert.Equal(t, "/A/C/D: unsupported", err.Error())
+}
@@ -0,0 +1,135 @@
+package girraph
+
+// Get the root of the tree containing node by following parent links.
+func Root[T any](node Tree[T]) Tree[T] {
+	for node.GetParent() != nil {
+		node = node.GetParent()
+	}
+	return node
+}
+
+// Get the number of ancestors of node; the root has depth 0.
+func Depth[T any](node Tree[T]) int {
+	depth := 0
+	for parent := node.GetParent(); parent != nil; parent = parent.GetParent() {
+		depth++
+	}
+	return depth
+}
+
+// Get the number of edges on the longest path from node down to a leaf; a leaf has height 0.  Heights are cached
+// until the node or one of its descendants is mutated.
+func Height[T any](node Tree[T]) int {
+	cache := treeCache(node)
+	if cache != nil && cache.hasHeight {
+		return cache.height
+	}
+	height := 0
+	for _, child := range node.GetChildren() {
+		if childHeight := Height(child) + 1; childHeight > height {
+			height = childHeight
+		}
+	}
+	if cache != nil {
+		cache.height = height
+		cache.hasHeight = true
+	}
+	return height
+}
+
+// Get the number of nodes in the subtree rooted at node, including node.  Sizes are cached until the node or one of
+// its descendants is mutated.
+func SubtreeSize[T any](node Tree[T]) int {
+	cache := treeCache(node)
+	if cache != nil && cache.hasSize {
+		return cache.size
+	}
+	size := 1
+	for _, child := range node.GetChildren() {
+		size += SubtreeSize(child)
+	}
+	if cache != nil {
+		cache.size = size
+		cache.hasSize = true
+	}
+	return size
+}
+
+func treeCache[T any](node Tree[T]) *nodeCache {
+	if c, ok := node.(cacheable); ok {
+		return c.getCache()
+	}
+	return nil
+}
+
+// Get the position of node among its parent's children, or -1 if it has no parent.
+func IndexInParent[T any](node Tree[T]) int {
+	parent := node.GetParent()
+	if parent == nil {
+		return -1
+	}
+	for i, child := range parent.GetChildren() {
+		if child == node {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get the other children of node's parent, in order.
+func Siblings[T any](node Tree[T]) []Tree[T] {
+	parent := node.GetParent()
+	if parent == nil {
+		return []Tree[T]{}
+	}
+	return removeNode(parent.GetChildren(), node)
+}
+
+// Get the child after node in its parent's children, or nil if it is the last.
+func NextSibling[T any](node Tree[T]) Tree[T] {
+	return siblingAt(node, 1)
+}
+
+// Get the child before node in its parent's children, or nil if it is the first.
+func PrevSibling[T any](node Tree[T]) Tree[T] {
+	return siblingAt(node, -1)
+}
+
+func siblingAt[T any](node Tree[T], offset int) Tree[T] {
+	index := IndexInParent(node)
+	if index < 0 {
+		return nil
+	}
+	children := node.GetParent().GetChildren()
+	if index+offset < 0 || index+offset >= len(children) {
+		return nil
+	}
+	return children[index+offset]
+}
+
+// Get the nodes without children below node, in depth-first order.
+func Leaves[T any](node Tree[T]) []Tree[T] {
+	var result []Tree[T]
+	Traverse(node, func(n Tree[T]) {
+		if len(n.GetChildren()) == 0 {
+			result = append(result, n)
+		}
+	})
+	return result
+}
+
+// Call the callback for each node below root, level by level, with its depth relative to root.  Iteration stops when
+// the callback returns false.
+func LevelOrder[T any](root Tree[T], callback func(node Tree[T], depth int) bool) {
+	level := []Tree[T]{root}
+	for depth := 0; len(level) > 0; depth++ {
+		var next []Tree[T]
+		for _, node := range level {
+			if !callback(node, depth) {
+				return
+			}
+			next = append(next, node.GetChildren()...)
+		}
+		level = next
+	}
+}
@@ -0,0 +1,63 @@
+package girraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree_Navigation(t *testing.T) {
+	tree := getTreeFixture()
+	nodeB := FindNodesByID(tree, "B")[0]
+	nodeC := FindNodesByID(tree, "C")[0]
+	nodeD := FindNodesByID(tree, "D")[0]
+
+	assert.Same(t, tree, Root(nodeD))
+	assert.Equal(t, 0, Depth(tree))
+	assert.Equal(t, 2, Depth(nodeD))
+	assert.Equal(t, -1, IndexInParent(tree))
+	assert.Equal(t, 1, IndexInParent(nodeC))
+	assert.Equal(t, []Tree[CustomTree]{nodeC}, Siblings(nodeB))
+	assert.Empty(t, Siblings(nodeD))
+	assert.Same(t, nodeC, NextSibling(nodeB))
+	assert.Nil(t, NextSibling(nodeC))
+	assert.Same(t, nodeB, PrevSibling(nodeC))
+	assert.Nil(t, PrevSibling(tree))
+	assert.Equal(t, []Tree[CustomTree]{nodeB, nodeD}, Leaves(tree))
+}
+
+func TestTree_Height(t *testing.T) {
+	tree := getTreeFixture()
+	nodeD := FindNodesByID(tree, "D")[0]
+	assert.Equal(t, 2, Height(tree))
+	assert.Equal(t, 0, Height(nodeD))
+	assert.Equal(t, 4, SubtreeSize(tree))
+
+	// Cached values are cleared by mutations below the root.
+	nodeD.AddChild(MakeTree[CustomTree]().SetID("E"))
+	assert.Equal(t, 3, Height(tree))
+	assert.Equal(t, 5, SubtreeSize(tree))
+	assert.Equal(t, 1, Height(nodeD))
+}
+
+func TestLevelOrder(t *testing.T) {
+	var ids []string
+	var depths []int
+	LevelOrder(getTreeFixture(), func(node Tree[CustomTree], depth int) bool {
+		ids = append(ids, node.GetID())
+		depths = append(depths, depth)
+		return true
+	})
+	assert.Equal(t, []string{"A", "B", "C", "D"}, ids)
+	assert.Equal(t, []int{0, 1, 1, 2}, depths)
+}
+
+func TestLevelOrder_Stop(t *testing.T) {
+	var ids []string
+	LevelOrder(getTreeFixture(), func(node Tree[CustomTree], depth int) bool {
+		ids = append(ids, node.GetID())
+		return depth < 1
+	})
+	require.Len(t, ids, 2)
+}
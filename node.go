@@ -1,5 +1,16 @@
 package girraph
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrCycle           = errors.New("graph contains a cycle")
+	ErrUnsupportedNode = errors.New("node implementation does not support this operation")
+)
+
 type Node[T any] interface {
 	SetID(string) T
 	GetID() string
@@ -10,9 +21,56 @@ type Node[T any] interface {
 	JSON() ([]byte, error)
 }
 
+// A node that carries meta, such as Graph[T] and Tree[T].
+type MetaNode[T any, M any] interface {
+	Node[T]
+	SetMeta(M) T
+	GetMeta() M
+}
+
 type NodeJSON[T any] struct {
 	ID       string
 	Meta     T
 	Children []*NodeJSON[T]
 	parents  []*NodeJSON[T]
 }
+
+// The IDs of the nodes walked from a root to a node, including both.
+type NodePath []string
+
+func (p NodePath) String() string {
+	return "/" + strings.Join(p, "/")
+}
+
+func containsNode[T any](nodes []T, node T) bool {
+	for _, n := range nodes {
+		if any(n) == any(node) {
+			return true
+		}
+	}
+	return false
+}
+
+func removeNode[T any](nodes []T, node T) []T {
+	result := make([]T, 0, len(nodes))
+	for _, n := range nodes {
+		if any(n) != any(node) {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+type shallowCopier[T any] interface {
+	shallowCopy() T
+}
+
+// Copy a node's ID and meta into a new node of the same type without any children or parents.
+func shallowCopy[T any](node T) (T, error) {
+	c, ok := any(node).(shallowCopier[T])
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("%w: %T", ErrUnsupportedNode, node)
+	}
+	return c.shallowCopy(), nil
+}
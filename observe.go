@@ -0,0 +1,308 @@
+package girraph
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// The kind of change described by an Event.
+type EventType string
+
+const (
+	// A node gained its first parent.
+	NodeAdded EventType = "node_added"
+	// A node lost its last parent.
+	NodeRemoved EventType = "node_removed"
+	// A child was inserted into its parent's children at Index.
+	EdgeAdded EventType = "edge_added"
+	// A child was removed from its parent's children at Index.
+	EdgeRemoved EventType = "edge_removed"
+	MetaChanged EventType = "meta_changed"
+	IDChanged   EventType = "id_changed"
+)
+
+// A change made by one of the node mutation methods.  The edge events of a mutation each describe a single step, so
+// applying them in order to the children before the mutation gives the children after it.  Parent links set directly
+// with AddParent, SetParent or SetParents do not emit events.
+type Event[T any] struct {
+	Type EventType
+	// The node that changed; for edge events, the child.
+	Node T
+	// The parent of the edge, or the parent that Node was added to or removed from.  Only set for edge and node events;
+	// use HasParent to check.
+	Parent T
+	// The index of the child in the parent's children, for edge events.
+	Index int
+	// The IDs before and after an IDChanged event.
+	OldID string
+	NewID string
+	// The meta before and after a MetaChanged event.
+	OldMeta any
+	NewMeta any
+}
+
+// Whether the event has a Parent, i.e. whether it is an edge or node event.  For other events Parent is the zero value
+// of T, which for a pointer node type is a typed nil, so any(event.Parent) != nil can't tell.
+func (e Event[T]) HasParent() bool {
+	switch e.Type {
+	case NodeAdded, NodeRemoved, EdgeAdded, EdgeRemoved:
+		return true
+	}
+	return false
+}
+
+// Receives the events of a node and its descendants.  Each call receives the events of a single mutation, or of every
+// mutation made inside a Batch.
+type Observer[T any] func(events []Event[T])
+
+type observerEntry[T any] struct {
+	observer Observer[T]
+}
+
+type observerList[T any] struct {
+	mu      sync.Mutex
+	entries []*observerEntry[T]
+	batch   int
+	pending []Event[T]
+}
+
+type observable[T any] interface {
+	getObservers() *observerList[T]
+}
+
+// The number of registered observers, so that mutations don't look for observers when there aren't any.
+var observerCount int64
+
+// Call observer synchronously with the events of node and its descendants, until cancel is called.
+func Observe[T Node[T]](node T, observer Observer[T]) (cancel func(), err error) {
+	o, ok := any(node).(observable[T])
+	if !ok {
+		return nil, fmt.Errorf("%w: %T cannot be observed", ErrUnsupportedNode, node)
+	}
+	list := o.getObservers()
+	entry := &observerEntry[T]{
+		observer: observer,
+	}
+	list.mu.Lock()
+	list.entries = append(list.entries, entry)
+	list.mu.Unlock()
+	atomic.AddInt64(&observerCount, 1)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			list.mu.Lock()
+			for i, existing := range list.entries {
+				if existing == entry {
+					list.entries = append(list.entries[:i:i], list.entries[i+1:]...)
+					break
+				}
+			}
+			list.mu.Unlock()
+			atomic.AddInt64(&observerCount, -1)
+		})
+	}, nil
+}
+
+// Observe node, sending the events of each call on the returned channel.  Mutations block while the channel is full,
+// so it must be drained until cancel is called.  Cancel closes the channel.
+func Subscribe[T Node[T]](node T, buffer int) (events <-chan []Event[T], cancel func(), err error) {
+	ch := make(chan []Event[T], buffer)
+	done := make(chan struct{})
+	var mu sync.Mutex
+	stop, err := Observe(node, func(events []Event[T]) {
+		mu.Lock()
+		defer mu.Unlock()
+		select {
+		case <-done:
+		default:
+			select {
+			case ch <- events:
+			case <-done:
+			}
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			stop()
+			// Unblock a pending send before waiting for it to finish.
+			close(done)
+			mu.Lock()
+			close(ch)
+			mu.Unlock()
+		})
+	}, nil
+}
+
+// Call fn, holding back the events of its mutations to root and its descendants until it returns, then deliver them
+// to each observer in a single call.  Batches may be nested; events are delivered when the outermost batch ends.
+func Batch[T Node[T]](root T, fn func()) {
+	var lists []*observerList[T]
+	visited := make(map[any]bool)
+	collect := func(node T) {
+		if visited[node] {
+			return
+		}
+		visited[node] = true
+		if o, ok := any(node).(observable[T]); ok {
+			lists = append(lists, o.getObservers())
+		}
+	}
+	// Events bubble up, so the observers of the ancestors see them too.
+	var ancestors func(node T)
+	ancestors = func(node T) {
+		for _, parent := range node.GetParents() {
+			if !visited[parent] {
+				collect(parent)
+				ancestors(parent)
+			}
+		}
+	}
+	WalkOnce[T](root, VisitorFuncs[T]{
+		OnEnter: func(node T, depth int) VisitAction {
+			collect(node)
+			return Continue
+		},
+	})
+	ancestors(root)
+
+	for _, list := range lists {
+		list.mu.Lock()
+		list.batch++
+		list.mu.Unlock()
+	}
+	defer func() {
+		for _, list := range lists {
+			list.mu.Lock()
+			list.batch--
+			var events []Event[T]
+			if list.batch == 0 {
+				events = list.pending
+				list.pending = nil
+			}
+			list.mu.Unlock()
+			if len(events) > 0 {
+				list.deliver(events)
+			}
+		}
+	}()
+	fn()
+}
+
+// Deliver the events of a mutation of origin to the observers of origin and its ancestors.
+func emit[T Node[T]](origin T, events ...Event[T]) {
+	if atomic.LoadInt64(&observerCount) == 0 || len(events) == 0 {
+		return
+	}
+	visited := make(map[any]bool)
+	var visit func(node T)
+	visit = func(node T) {
+		if visited[node] {
+			return
+		}
+		visited[node] = true
+		if o, ok := any(node).(observable[T]); ok {
+			list := o.getObservers()
+			list.mu.Lock()
+			batched := list.batch > 0
+			if batched {
+				list.pending = append(list.pending, events...)
+			}
+			list.mu.Unlock()
+			if !batched {
+				list.deliver(events)
+			}
+		}
+		for _, parent := range node.GetParents() {
+			visit(parent)
+		}
+	}
+	visit(origin)
+}
+
+func (l *observerList[T]) deliver(events []Event[T]) {
+	l.mu.Lock()
+	entries := append([]*observerEntry[T]{}, l.entries...)
+	l.mu.Unlock()
+	for _, entry := range entries {
+		entry.observer(append([]Event[T]{}, events...))
+	}
+}
+
+// Get the events for replacing the children of parent.  The old children are removed from last to first, then the new
+// children are added in order.  orphans holds the new children that had no parents before the change.
+func childrenEvents[T Node[T]](parent T, old, children []T, orphans map[any]bool) []Event[T] {
+	var events []Event[T]
+	for i := len(old) - 1; i >= 0; i-- {
+		events = append(events, Event[T]{
+			Type:   EdgeRemoved,
+			Node:   old[i],
+			Parent: parent,
+			Index:  i,
+		})
+	}
+	for i, child := range children {
+		events = append(events, Event[T]{
+			Type:   EdgeAdded,
+			Node:   child,
+			Parent: parent,
+			Index:  i,
+		})
+	}
+	seen := make(map[any]bool)
+	for _, child := range old {
+		if !seen[child] && len(child.GetParents()) == 0 {
+			events = append(events, Event[T]{
+				Type:   NodeRemoved,
+				Node:   child,
+				Parent: parent,
+			})
+		}
+		seen[child] = true
+	}
+	for _, child := range children {
+		if orphans[child] {
+			events = append(events, Event[T]{
+				Type:   NodeAdded,
+				Node:   child,
+				Parent: parent,
+			})
+			delete(orphans, child)
+		}
+	}
+	return events
+}
+
+// Get the new children that have no parents yet.
+func orphanChildren[T Node[T]](children []T) map[any]bool {
+	result := make(map[any]bool)
+	for _, child := range children {
+		if len(child.GetParents()) == 0 {
+			result[child] = true
+		}
+	}
+	return result
+}
+
+// Get the events for appending child to the children of parent.
+func addChildEvents[T Node[T]](parent, child T, index int, orphan bool) []Event[T] {
+	events := []Event[T]{{
+		Type:   EdgeAdded,
+		Node:   child,
+		Parent: parent,
+		Index:  index,
+	}}
+	if orphan {
+		events = append(events, Event[T]{
+			Type:   NodeAdded,
+			Node:   child,
+			Parent: parent,
+		})
+	}
+	return events
+}
@@ -0,0 +1,147 @@
+package girraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedEvent struct {
+	Type   EventType
+	Node   string
+	Parent string
+	Index  int
+}
+
+func recordEvents[T Node[T]](t *testing.T, node T) (*[][]recordedEvent, func()) {
+	var calls [][]recordedEvent
+	cancel, err := Observe(node, func(events []Event[T]) {
+		var call []recordedEvent
+		for _, event := range events {
+			recorded := recordedEvent{
+				Type:  event.Type,
+				Node:  event.Node.GetID(),
+				Index: event.Index,
+			}
+			if event.HasParent() {
+				recorded.Parent = event.Parent.GetID()
+			}
+			call = append(call, recorded)
+		}
+		calls = append(calls, call)
+	})
+	require.Nil(t, err)
+	return &calls, cancel
+}
+
+func TestObserve_Graph(t *testing.T) {
+	root := getGraphFixture()
+	calls, cancel := recordEvents(t, root)
+
+	e := MakeGraph[CustomGraph]().SetID("E")
+	d := FindNodesByID(root, "D")[0]
+	d.AddChild(e)
+	e.SetMeta(&customGraph{Name: "node E"})
+	e.SetID("F")
+
+	assert.Equal(t, [][]recordedEvent{
+		{{Type: EdgeAdded, Node: "E", Parent: "D"}, {Type: NodeAdded, Node: "E", Parent: "D"}},
+		{{Type: MetaChanged, Node: "E"}},
+		{{Type: IDChanged, Node: "F"}},
+	}, *calls, "events from a shared node are delivered once")
+
+	cancel()
+	d.SetChildren([]Graph[CustomGraph]{})
+	assert.Len(t, *calls, 3, "no events are delivered after cancelling")
+}
+
+func TestObserve_SetChildren(t *testing.T) {
+	root := getTreeFixture()
+	calls, _ := recordEvents(t, root)
+
+	b := root.GetChildren()[0]
+	e := MakeTree[CustomTree]().SetID("E")
+	root.SetChildren([]Tree[CustomTree]{e, b})
+
+	assert.Equal(t, [][]recordedEvent{{
+		{Type: EdgeRemoved, Node: "C", Parent: "A", Index: 1},
+		{Type: EdgeRemoved, Node: "B", Parent: "A", Index: 0},
+		{Type: EdgeAdded, Node: "E", Parent: "A", Index: 0},
+		{Type: EdgeAdded, Node: "B", Parent: "A", Index: 1},
+		{Type: NodeRemoved, Node: "C", Parent: "A"},
+		{Type: NodeAdded, Node: "E", Parent: "A"},
+	}}, *calls)
+}
+
+func TestObserve_MetaEvent(t *testing.T) {
+	root := getGraphFixture()
+	var events []Event[Graph[CustomGraph]]
+	_, err := Observe(root, func(e []Event[Graph[CustomGraph]]) {
+		events = append(events, e...)
+	})
+	require.Nil(t, err)
+
+	old := root.GetMeta()
+	root.SetMeta(&customGraph{Name: "renamed"})
+	require.Len(t, events, 1)
+	assert.Equal(t, old, events[0].OldMeta)
+	assert.Equal(t, "renamed", events[0].NewMeta.(CustomGraph).GetName())
+}
+
+func TestBatch(t *testing.T) {
+	root := getGraphFixture()
+	calls, _ := recordEvents(t, root)
+	d := FindNodesByID(root, "D")[0]
+	dCalls, _ := recordEvents(t, d)
+
+	Batch(root, func() {
+		d.SetID("D2")
+		Batch(d, func() {
+			d.AddChild(MakeGraph[CustomGraph]().SetID("E"))
+		})
+		assert.Empty(t, *calls, "events are held until the outermost batch ends")
+	})
+
+	expected := [][]recordedEvent{{
+		{Type: IDChanged, Node: "D2"},
+		{Type: EdgeAdded, Node: "E", Parent: "D2"},
+		{Type: NodeAdded, Node: "E", Parent: "D2"},
+	}}
+	assert.Equal(t, expected, *calls)
+	assert.Equal(t, expected, *dCalls)
+}
+
+func TestSubscribe(t *testing.T) {
+	root := getTreeFixture()
+	events, cancel, err := Subscribe(root, 1)
+	require.Nil(t, err)
+
+	done := make(chan []EventType)
+	go func() {
+		var types []EventType
+		for batch := range events {
+			for _, event := range batch {
+				types = append(types, event.Type)
+			}
+		}
+		done <- types
+	}()
+
+	root.SetID("root")
+	root.AddChild(MakeTree[CustomTree]().SetID("E"))
+	root.SetMeta(&customTree{Name: "root"})
+	cancel()
+
+	assert.Equal(t, []EventType{IDChanged, EdgeAdded, NodeAdded, MetaChanged}, <-done)
+}
+
+func TestEvent_HasParent(t *testing.T) {
+	assert.True(t, Event[*customGraph]{Type: EdgeAdded}.HasParent())
+	assert.True(t, Event[*customGraph]{Type: NodeRemoved}.HasParent())
+
+	// The zero value of a pointer node is a typed nil, which is not nil as an interface.
+	event := Event[*customGraph]{Type: MetaChanged}
+	assert.False(t, event.HasParent())
+	assert.True(t, any(event.Parent) != nil)
+}
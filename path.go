@@ -0,0 +1,187 @@
+package girraph
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNoPath         = errors.New("no path between nodes")
+	ErrNegativeWeight = errors.New("weights must not be negative")
+)
+
+// Weight functions for the path algorithms.  The cost of a path is the sum of the weights of its nodes and edges.  A
+// nil function weighs nothing.
+type Weights[T any] struct {
+	Node func(meta T) float64
+	Edge func(parent, child Graph[T]) float64
+}
+
+func (w Weights[T]) node(node Graph[T]) float64 {
+	if w.Node == nil {
+		return 0
+	}
+	return w.Node(node.GetMeta())
+}
+
+func (w Weights[T]) edge(parent, child Graph[T]) float64 {
+	if w.Edge == nil {
+		return 0
+	}
+	return w.Edge(parent, child)
+}
+
+// Use the edge weights stored in edges as an edge weight function.
+func EdgeWeight[T any, E any](edges *Edges[E]) func(parent, child Graph[T]) float64 {
+	return func(parent, child Graph[T]) float64 {
+		return edges.Weight(parent.GetID(), child.GetID())
+	}
+}
+
+// A path from parent to child, and its total cost.
+type Path[T any] struct {
+	Nodes []Graph[T]
+	Cost  float64
+}
+
+func (p Path[T]) IDs() []string {
+	result := make([]string, len(p.Nodes))
+	for i, node := range p.Nodes {
+		result[i] = node.GetID()
+	}
+	return result
+}
+
+// Find the cheapest path from a node down to one of its descendants using Dijkstra's algorithm.
+func ShortestPath[T any](from, to Graph[T], weights Weights[T]) (Path[T], error) {
+	start := weights.node(from)
+	if start < 0 {
+		return Path[T]{}, fmt.Errorf("%w: node %q", ErrNegativeWeight, from.GetID())
+	}
+
+	cost := map[Graph[T]]float64{from: start}
+	previous := make(map[Graph[T]]Graph[T])
+	done := make(map[Graph[T]]bool)
+	queue := &pathQueue[T]{}
+	heap.Push(queue, pathItem[T]{node: from, cost: start})
+
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(pathItem[T])
+		if done[current.node] {
+			continue
+		}
+		done[current.node] = true
+		if current.node == to {
+			return Path[T]{
+				Nodes: pathTo(previous, from, to),
+				Cost:  current.cost,
+			}, nil
+		}
+		for _, child := range current.node.GetChildren() {
+			nodeWeight := weights.node(child)
+			edgeWeight := weights.edge(current.node, child)
+			if nodeWeight < 0 || edgeWeight < 0 {
+				return Path[T]{}, fmt.Errorf("%w: edge %q -> %q", ErrNegativeWeight, current.node.GetID(), child.GetID())
+			}
+			childCost := current.cost + edgeWeight + nodeWeight
+			if existing, seen := cost[child]; !seen || childCost < existing {
+				cost[child] = childCost
+				previous[child] = current.node
+				heap.Push(queue, pathItem[T]{node: child, cost: childCost})
+			}
+		}
+	}
+
+	return Path[T]{}, fmt.Errorf("%w: %q -> %q", ErrNoPath, from.GetID(), to.GetID())
+}
+
+func pathTo[T any](previous map[Graph[T]]Graph[T], from, to Graph[T]) []Graph[T] {
+	result := []Graph[T]{to}
+	for node := to; node != from; {
+		node = previous[node]
+		result = append([]Graph[T]{node}, result...)
+	}
+	return result
+}
+
+type pathItem[T any] struct {
+	node Graph[T]
+	cost float64
+}
+
+type pathQueue[T any] []pathItem[T]
+
+func (q pathQueue[T]) Len() int {
+	return len(q)
+}
+
+func (q pathQueue[T]) Less(i, j int) bool {
+	return q[i].cost < q[j].cost
+}
+
+func (q pathQueue[T]) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *pathQueue[T]) Push(item any) {
+	*q = append(*q, item.(pathItem[T]))
+}
+
+func (q *pathQueue[T]) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// Find the most expensive path from root down to a leaf.  When nodes are tasks weighted by their duration and children
+// must be done before their parents, this is the minimum time needed to complete root.  Ties are broken in favor of
+// earlier children.
+func CriticalPath[T any](root Graph[T], weights Weights[T]) (Path[T], error) {
+	c := &criticalPath[T]{
+		weights: weights,
+		best:    make(map[Graph[T]]Path[T]),
+		onPath:  make(map[Graph[T]]bool),
+	}
+	return c.longest(root)
+}
+
+type criticalPath[T any] struct {
+	weights Weights[T]
+	best    map[Graph[T]]Path[T]
+	onPath  map[Graph[T]]bool
+}
+
+func (c *criticalPath[T]) longest(node Graph[T]) (Path[T], error) {
+	if path, ok := c.best[node]; ok {
+		return path, nil
+	}
+	if c.onPath[node] {
+		return Path[T]{}, fmt.Errorf("%w: node %q is its own descendant", ErrCycle, node.GetID())
+	}
+	c.onPath[node] = true
+	defer delete(c.onPath, node)
+
+	var tail Path[T]
+	for i, child := range node.GetChildren() {
+		path, err := c.longest(child)
+		if err != nil {
+			return Path[T]{}, err
+		}
+		cost := c.weights.edge(node, child) + path.Cost
+		if i == 0 || cost > tail.Cost {
+			tail = Path[T]{
+				Nodes: path.Nodes,
+				Cost:  cost,
+			}
+		}
+	}
+
+	result := Path[T]{
+		Nodes: append([]Graph[T]{node}, tail.Nodes...),
+		Cost:  c.weights.node(node) + tail.Cost,
+	}
+	c.best[node] = result
+	return result, nil
+}
@@ -0,0 +1,76 @@
+package girraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nodeWeight(meta int) float64 {
+	return float64(meta)
+}
+
+func getWeightedFixture() Graph[int] {
+	nodeD := MakeGraph[int]().SetID("D").SetMeta(1)
+	return MakeGraph[int]().SetID("A").SetMeta(1).SetChildren([]Graph[int]{
+		MakeGraph[int]().SetID("B").SetMeta(2).AddChild(nodeD),
+		MakeGraph[int]().SetID("C").SetMeta(5).SetChildren([]Graph[int]{
+			nodeD,
+			MakeGraph[int]().SetID("E").SetMeta(1),
+		}),
+	})
+}
+
+func TestShortestPath(t *testing.T) {
+	graph := getWeightedFixture()
+	nodeD := FindNodesByID(graph, "D")[0]
+
+	path, err := ShortestPath(graph, nodeD, Weights[int]{Node: nodeWeight})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"A", "B", "D"}, path.IDs())
+	assert.Equal(t, 4.0, path.Cost)
+}
+
+func TestShortestPath_EdgeWeights(t *testing.T) {
+	graph := getWeightedFixture()
+	nodeD := FindNodesByID(graph, "D")[0]
+	edges := MakeEdges[struct{}]().Set(Edge[struct{}]{Parent: "A", Child: "B", Weight: 10})
+
+	path, err := ShortestPath(graph, nodeD, Weights[int]{Node: nodeWeight, Edge: EdgeWeight[int](edges)})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"A", "C", "D"}, path.IDs())
+	assert.Equal(t, 7.0, path.Cost)
+}
+
+func TestShortestPath_NoPath(t *testing.T) {
+	graph := getWeightedFixture()
+	nodeE := FindNodesByID(graph, "E")[0]
+
+	_, err := ShortestPath(nodeE, graph, Weights[int]{Node: nodeWeight})
+	assert.True(t, errors.Is(err, ErrNoPath))
+}
+
+func TestShortestPath_NegativeWeight(t *testing.T) {
+	graph := getWeightedFixture()
+	nodeD := FindNodesByID(graph, "D")[0]
+
+	_, err := ShortestPath(graph, nodeD, Weights[int]{Node: func(int) float64 { return -1 }})
+	assert.True(t, errors.Is(err, ErrNegativeWeight))
+}
+
+func TestCriticalPath(t *testing.T) {
+	path, err := CriticalPath(getWeightedFixture(), Weights[int]{Node: nodeWeight})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"A", "C", "D"}, path.IDs())
+	assert.Equal(t, 7.0, path.Cost)
+}
+
+func TestCriticalPath_Cycle(t *testing.T) {
+	graph := getWeightedFixture()
+	FindNodesByID(graph, "E")[0].AddChild(graph)
+
+	_, err := CriticalPath(graph, Weights[int]{Node: nodeWeight})
+	assert.True(t, errors.Is(err, ErrCycle))
+}
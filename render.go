@@ -0,0 +1,106 @@
+package girraph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Options for Render.
+type RenderOptions[M any] struct {
+	// Gets the label of a node from its meta.  By default the name is used, as for selectors, or the ID if the meta has
+	// no name.
+	Label func(meta M) string
+	// The number of levels below the root to render; deeper nodes are replaced with "...".  Zero renders every level.
+	MaxDepth int
+	// Draw with ASCII characters instead of box-drawing characters.
+	ASCII bool
+}
+
+type renderStyle struct {
+	branch, last, pipe, space string
+}
+
+var (
+	unicodeStyle = renderStyle{
+		branch: "├── ",
+		last:   "└── ",
+		pipe:   "│   ",
+		space:  "    ",
+	}
+	asciiStyle = renderStyle{
+		branch: "|-- ",
+		last:   "`-- ",
+		pipe:   "|   ",
+		space:  "    ",
+	}
+)
+
+// Render the nodes below root as an outline, like the tree command, one node per line.  A node with multiple parents
+// is only expanded the first time it is reached; later occurrences are marked "(see above)".  Edges back to a node
+// that is already being rendered are marked "(cycle)".
+func Render[T MetaNode[T, M], M any](root T, options RenderOptions[M]) string {
+	r := &renderer[T, M]{
+		options:  options,
+		style:    unicodeStyle,
+		rendered: make(map[any]bool),
+		onPath:   make(map[any]bool),
+	}
+	if options.ASCII {
+		r.style = asciiStyle
+	}
+	r.line("", root, "")
+	r.children(root, "", 1)
+	return r.out.String()
+}
+
+type renderer[T MetaNode[T, M], M any] struct {
+	options  RenderOptions[M]
+	style    renderStyle
+	out      strings.Builder
+	rendered map[any]bool
+	onPath   map[any]bool
+}
+
+func (r *renderer[T, M]) label(node T) string {
+	var label string
+	if r.options.Label != nil {
+		label = r.options.Label(node.GetMeta())
+	} else if name, ok := metaName(node.GetMeta()); ok {
+		label = name
+	}
+	if label == "" {
+		label = node.GetID()
+	}
+	return strings.ReplaceAll(label, "\n", " ")
+}
+
+func (r *renderer[T, M]) line(prefix string, node T, note string) {
+	fmt.Fprintf(&r.out, "%s%s%s\n", prefix, r.label(node), note)
+}
+
+func (r *renderer[T, M]) children(node T, indent string, depth int) {
+	r.rendered[node] = true
+	r.onPath[node] = true
+	defer delete(r.onPath, node)
+
+	children := node.GetChildren()
+	if len(children) > 0 && r.options.MaxDepth > 0 && depth > r.options.MaxDepth {
+		fmt.Fprintf(&r.out, "%s%s...\n", indent, r.style.last)
+		return
+	}
+	for i, child := range children {
+		branch, next := r.style.branch, r.style.pipe
+		if i == len(children)-1 {
+			branch, next = r.style.last, r.style.space
+		}
+		switch {
+		case r.onPath[child]:
+			r.line(indent+branch, child, " (cycle)")
+		case r.rendered[child]:
+			r.line(indent+branch, child, " (see above)")
+		default:
+			r.line(indent+branch, child, "")
+			r.children(child, indent+next, depth+1)
+		}
+	}
+}
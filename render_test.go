@@ -0,0 +1,47 @@
+package girraph
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+func assertGolden(t *testing.T, name, actual string) {
+	path := filepath.Join("testdata", name+".golden")
+	if *updateGolden {
+		require.Nil(t, os.WriteFile(path, []byte(actual), 0644))
+	}
+	expected, err := os.ReadFile(path)
+	require.Nil(t, err)
+	assert.Equal(t, string(expected), actual)
+}
+
+func TestRender_Graph(t *testing.T) {
+	assertGolden(t, "render_graph", Render(getGraphFixture(), RenderOptions[CustomGraph]{}))
+}
+
+func TestRender_Tree(t *testing.T) {
+	assertGolden(t, "render_tree_ascii", Render(getTreeFixture(), RenderOptions[CustomTree]{
+		ASCII: true,
+	}))
+}
+
+func TestRender_MaxDepth(t *testing.T) {
+	assertGolden(t, "render_max_depth", Render(getTreeFixture(), RenderOptions[CustomTree]{
+		MaxDepth: 1,
+		Label: func(meta CustomTree) string {
+			return "[" + meta.GetName() + "]"
+		},
+	}))
+}
+
+func TestRender_Cycle(t *testing.T) {
+	a, _ := getCyclicGraphFixture()
+	assertGolden(t, "render_cycle", Render(a, RenderOptions[string]{}))
+}
@@ -0,0 +1,408 @@
+package girraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A parsed path-style selector, like "/root/*/src//tests" or "//[type=decision]/[type=condition]".
+//
+// A selector is a list of steps separated by "/", which selects the children of the nodes matched so far, or "//",
+// which selects all of their descendants.  The first step is matched against the root, or with a leading "//",
+// against the root and all of its descendants.  Each step is a name test followed by any number of predicates:
+//
+//	name           a node whose ID or name is name; use double quotes for names containing special characters
+//	*              any node
+//	[attr=value]   a node whose attribute equals value; "!=" is also supported, and "[attr]" checks that it is set
+//
+// Names come from an accessor registered with RegisterNameAccessor, or from GetName if the meta has one.  Attributes
+// are read from the JSON encoding of the meta, matching field names case-insensitively; nested fields are separated
+// by ".".  The "id" attribute is always the node ID.
+type Selector struct {
+	expr  string
+	steps []selectorStep
+}
+
+type selectorStep struct {
+	descendants bool
+	wildcard    bool
+	name        string
+	predicates  []selectorPredicate
+}
+
+type selectorPredicate struct {
+	key    string
+	op     string
+	value  string
+	exists bool
+}
+
+// An error in a selector, pointing at the offending position.
+type SelectorError struct {
+	Selector string
+	Position int
+	Message  string
+}
+
+func (e *SelectorError) Error() string {
+	return fmt.Sprintf("invalid selector %q at position %d: %s", e.Selector, e.Position, e.Message)
+}
+
+// Get the selector with a caret under the offending position.
+func (e *SelectorError) Context() string {
+	return e.Selector + "\n" + strings.Repeat(" ", e.Position) + "^"
+}
+
+func ParseSelector(expr string) (*Selector, error) {
+	p := &selectorParser{
+		expr: expr,
+	}
+	steps, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Selector{
+		expr:  expr,
+		steps: steps,
+	}, nil
+}
+
+func MustParseSelector(expr string) *Selector {
+	selector, err := ParseSelector(expr)
+	if err != nil {
+		panic(err)
+	}
+	return selector
+}
+
+func (s *Selector) String() string {
+	return s.expr
+}
+
+type selectorParser struct {
+	expr string
+	pos  int
+}
+
+func (p *selectorParser) fail(message string, args ...any) error {
+	return &SelectorError{
+		Selector: p.expr,
+		Position: p.pos,
+		Message:  fmt.Sprintf(message, args...),
+	}
+}
+
+func (p *selectorParser) done() bool {
+	return p.pos >= len(p.expr)
+}
+
+func (p *selectorParser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.expr[p.pos]
+}
+
+func (p *selectorParser) consume(prefix string) bool {
+	if strings.HasPrefix(p.expr[p.pos:], prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+	return false
+}
+
+func (p *selectorParser) skipSpaces() {
+	for p.peek() == ' ' {
+		p.pos++
+	}
+}
+
+func (p *selectorParser) parse() ([]selectorStep, error) {
+	if p.expr == "" {
+		return nil, p.fail("selector is empty")
+	}
+	var steps []selectorStep
+	descendants := false
+	if p.consume("//") {
+		descendants = true
+	} else {
+		p.consume("/")
+	}
+	for {
+		step, err := p.parseStep(descendants)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+		if p.done() {
+			return steps, nil
+		}
+		if p.consume("//") {
+			descendants = true
+		} else if p.consume("/") {
+			descendants = false
+		} else {
+			return nil, p.fail("unexpected %q, expected \"/\"", p.peek())
+		}
+	}
+}
+
+func (p *selectorParser) parseStep(descendants bool) (selectorStep, error) {
+	step := selectorStep{
+		descendants: descendants,
+	}
+	switch p.peek() {
+	case '*':
+		p.pos++
+		step.wildcard = true
+	case '"':
+		name, err := p.parseQuoted()
+		if err != nil {
+			return step, err
+		}
+		step.name = name
+	default:
+		step.name = p.parseUntil("/[]\"")
+		step.wildcard = step.name == ""
+		if step.wildcard && p.peek() != '[' {
+			if p.done() {
+				return step, p.fail("expected a step")
+			}
+			return step, p.fail("unexpected %q, expected a name, \"*\" or \"[\"", p.peek())
+		}
+	}
+	for p.peek() == '[' {
+		predicate, err := p.parsePredicate()
+		if err != nil {
+			return step, err
+		}
+		step.predicates = append(step.predicates, predicate)
+	}
+	return step, nil
+}
+
+func (p *selectorParser) parsePredicate() (selectorPredicate, error) {
+	predicate := selectorPredicate{}
+	p.pos++
+	p.skipSpaces()
+	predicate.key = strings.TrimSpace(p.parseUntil("]=!\"/["))
+	if predicate.key == "" {
+		return predicate, p.fail("expected an attribute name")
+	}
+	switch {
+	case p.consume("]"):
+		predicate.exists = true
+		return predicate, nil
+	case p.consume("!="):
+		predicate.op = "!="
+	case p.consume("="):
+		predicate.op = "="
+	default:
+		return predicate, p.fail("expected \"=\", \"!=\" or \"]\"")
+	}
+	p.skipSpaces()
+	if p.peek() == '"' {
+		value, err := p.parseQuoted()
+		if err != nil {
+			return predicate, err
+		}
+		predicate.value = value
+		p.skipSpaces()
+	} else {
+		predicate.value = strings.TrimSpace(p.parseUntil("]\"/["))
+	}
+	if !p.consume("]") {
+		return predicate, p.fail("expected \"]\"")
+	}
+	return predicate, nil
+}
+
+func (p *selectorParser) parseUntil(stop string) string {
+	start := p.pos
+	for !p.done() && !strings.ContainsRune(stop, rune(p.peek())) {
+		p.pos++
+	}
+	return p.expr[start:p.pos]
+}
+
+func (p *selectorParser) parseQuoted() (string, error) {
+	start := p.pos
+	p.pos++
+	value := p.parseUntil("\"")
+	if !p.consume("\"") {
+		p.pos = start
+		return "", p.fail("unterminated quoted string")
+	}
+	return value, nil
+}
+
+var nameAccessors sync.Map
+
+// Register the function used to get the name of meta of type M for selectors.  Calling unregister restores the
+// accessor registered before, if any.
+func RegisterNameAccessor[M any](accessor func(meta M) string) (unregister func()) {
+	key := reflect.TypeOf((*M)(nil)).Elem()
+	previous, hadPrevious := nameAccessors.Swap(key, accessor)
+	return func() {
+		if hadPrevious {
+			nameAccessors.Store(key, previous)
+		} else {
+			nameAccessors.Delete(key)
+		}
+	}
+}
+
+func metaName[M any](meta M) (string, bool) {
+	if accessor, ok := nameAccessors.Load(reflect.TypeOf((*M)(nil)).Elem()); ok {
+		return accessor.(func(M) string)(meta), true
+	}
+	if named, ok := any(meta).(interface{ GetName() string }); ok {
+		return named.GetName(), true
+	}
+	return "", false
+}
+
+// Parse the selector and evaluate it against root.
+func Select[T MetaNode[T, M], M any](root T, selector string) (QueryResult[T], error) {
+	parsed, err := ParseSelector(selector)
+	if err != nil {
+		return QueryResult[T]{}, err
+	}
+	return Evaluate(root, parsed), nil
+}
+
+// Find the nodes below root matched by the selector, in depth-first order.
+func Evaluate[T MetaNode[T, M], M any](root T, selector *Selector) QueryResult[T] {
+	e := &evaluation[T, M]{
+		order:      make(map[any]int),
+		attributes: make(map[any]map[string]any),
+	}
+	var all []T
+	WalkOnce[T](root, VisitorFuncs[T]{
+		OnEnter: func(node T, depth int) VisitAction {
+			e.order[node] = len(all)
+			all = append(all, node)
+			return Continue
+		},
+	})
+
+	var matched []T
+	for i, step := range selector.steps {
+		var candidates []T
+		switch {
+		case i == 0 && step.descendants:
+			candidates = all
+		case i == 0:
+			candidates = []T{root}
+		default:
+			candidates = e.candidates(matched, step.descendants)
+		}
+		matched = nil
+		for _, candidate := range candidates {
+			if e.matches(candidate, step) {
+				matched = append(matched, candidate)
+			}
+		}
+	}
+
+	var result QueryResult[T]
+	for _, node := range matched {
+		result.AddNode(node)
+	}
+	return result
+}
+
+type evaluation[T MetaNode[T, M], M any] struct {
+	order      map[any]int
+	attributes map[any]map[string]any
+}
+
+// Get the distinct children or descendants of the nodes, in depth-first order.
+func (e *evaluation[T, M]) candidates(nodes []T, descendants bool) []T {
+	found := make(map[any]bool)
+	var result []T
+	var add func(T)
+	add = func(node T) {
+		for _, child := range node.GetChildren() {
+			if found[child] {
+				continue
+			}
+			found[child] = true
+			result = append(result, child)
+			if descendants {
+				add(child)
+			}
+		}
+	}
+	for _, node := range nodes {
+		add(node)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return e.order[result[i]] < e.order[result[j]]
+	})
+	return result
+}
+
+func (e *evaluation[T, M]) matches(node T, step selectorStep) bool {
+	if !step.wildcard && node.GetID() != step.name {
+		name, ok := metaName(node.GetMeta())
+		if !ok || name != step.name {
+			return false
+		}
+	}
+	for _, predicate := range step.predicates {
+		value, exists := e.attribute(node, predicate.key)
+		switch {
+		case predicate.exists && !exists:
+			return false
+		case predicate.op == "=" && (!exists || value != predicate.value):
+			return false
+		case predicate.op == "!=" && exists && value == predicate.value:
+			return false
+		}
+	}
+	return true
+}
+
+func (e *evaluation[T, M]) attribute(node T, key string) (string, bool) {
+	if strings.EqualFold(key, "id") {
+		return node.GetID(), true
+	}
+	fields, ok := e.attributes[node]
+	if !ok {
+		data, err := json.Marshal(node.GetMeta())
+		if err == nil {
+			_ = json.Unmarshal(data, &fields)
+		}
+		e.attributes[node] = fields
+	}
+	var value any = fields
+	for _, part := range strings.Split(key, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		value = nil
+		for field, fieldValue := range object {
+			if strings.EqualFold(field, part) {
+				value = fieldValue
+				break
+			}
+		}
+		if value == nil {
+			return "", false
+		}
+	}
+	if s, ok := value.(string); ok {
+		return s, true
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", false
+	}
+	return string(encoded), true
+}
@@ -0,0 +1,108 @@
+package girraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type selectorMeta struct {
+	Title string
+	Type  string
+	Task  *selectorTask
+}
+
+type selectorTask struct {
+	Priority int
+}
+
+func makeSelectorNode(id, title, nodeType string) Graph[*selectorMeta] {
+	return MakeGraph[*selectorMeta]().SetID(id).SetMeta(&selectorMeta{Title: title, Type: nodeType})
+}
+
+func getSelectorFixture() Graph[*selectorMeta] {
+	task := makeSelectorNode("task-2", "tests", "task")
+	task.GetMeta().Task = &selectorTask{Priority: 2}
+	return makeSelectorNode("root", "project", "task").SetChildren([]Graph[*selectorMeta]{
+		makeSelectorNode("app", "app", "task").SetChildren([]Graph[*selectorMeta]{
+			makeSelectorNode("src", "src", "task").AddChild(
+				makeSelectorNode("pkg", "pkg", "task").AddChild(task),
+			),
+		}),
+		makeSelectorNode("decision", "review", "decision").SetChildren([]Graph[*selectorMeta]{
+			makeSelectorNode("approved", "approved", "condition").AddChild(task),
+			makeSelectorNode("rejected", "rejected", "condition"),
+		}),
+	})
+}
+
+func selectIDs(t *testing.T, root Graph[*selectorMeta], selector string) []string {
+	result, err := Select(root, selector)
+	require.Nil(t, err)
+	var ids []string
+	for _, node := range result.GetNodes() {
+		ids = append(ids, node.GetID())
+	}
+	return ids
+}
+
+func TestSelect(t *testing.T) {
+	t.Cleanup(RegisterNameAccessor(func(meta *selectorMeta) string {
+		return meta.Title
+	}))
+	graph := getSelectorFixture()
+
+	assert.Equal(t, []string{"root"}, selectIDs(t, graph, "/root"))
+	assert.Equal(t, []string{"root"}, selectIDs(t, graph, "project"))
+	assert.Equal(t, []string{"src"}, selectIDs(t, graph, "/root/*/src"))
+	assert.Equal(t, []string{"task-2"}, selectIDs(t, graph, "/root/*/src//tests"))
+	assert.Equal(t, []string{"task-2"}, selectIDs(t, graph, "//tests"))
+	assert.Equal(t, []string{"approved", "rejected"}, selectIDs(t, graph, "//[type=decision]/[type=condition]"))
+	assert.Equal(t, []string{"rejected"}, selectIDs(t, graph, `//*[type=condition][ id != approved ]`))
+	assert.Equal(t, []string{"task-2"}, selectIDs(t, graph, "//[task]"))
+	assert.Equal(t, []string{"task-2"}, selectIDs(t, graph, "//[task.priority=2]"))
+	assert.Equal(t, []string{"app"}, selectIDs(t, graph, `/root/"app"`))
+	assert.Empty(t, selectIDs(t, graph, "/app"))
+}
+
+func TestRegisterNameAccessor_Unregister(t *testing.T) {
+	meta := &selectorMeta{Title: "title"}
+	unregister := RegisterNameAccessor(func(meta *selectorMeta) string {
+		return meta.Title
+	})
+	name, ok := metaName(meta)
+	assert.True(t, ok)
+	assert.Equal(t, "title", name)
+
+	unregister()
+	_, ok = metaName(meta)
+	assert.False(t, ok)
+}
+
+func TestParseSelector_Errors(t *testing.T) {
+	tests := map[string]int{
+		"":                 0,
+		"/root/":           6,
+		"/root/]":          6,
+		"//[]":             3,
+		"//[type":          7,
+		"//[type=decision": 16,
+		"//[type=a]x":      10,
+		`/"root`:           1,
+	}
+	for selector, position := range tests {
+		_, err := ParseSelector(selector)
+		var selectorErr *SelectorError
+		require.True(t, errors.As(err, &selectorErr), selector)
+		assert.Equal(t, position, selectorErr.Position, selector)
+	}
+}
+
+func TestSelectorError(t *testing.T) {
+	_, err := ParseSelector("//[type=decision/x")
+	require.NotNil(t, err)
+	assert.Equal(t, `invalid selector "//[type=decision/x" at position 16: expected "]"`, err.Error())
+	assert.Equal(t, "//[type=decision/x\n                ^", err.(*SelectorError).Context())
+}
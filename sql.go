@@ -0,0 +1,575 @@
+package girraph
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// How a graph is laid out in relational tables.
+type SQLLayout int
+
+const (
+	// A node table and an edge table of parent_id, child_id and position.
+	AdjacencyList SQLLayout = iota
+	// A node table and a closure table of every ancestor_id, descendant_id pair with the length of the shortest path
+	// between them, including each node paired with itself at depth 0.
+	ClosureTable
+	// A node table with the lft and rgt bounds of each node's subtree, and its depth.  Trees only.
+	NestedSets
+	// A node table with the path of IDs from the root to each node, its depth and its position in its parent.  Trees
+	// only; IDs must not contain "/".
+	MaterializedPath
+)
+
+var ErrSQLImport = errors.New("invalid SQL rows")
+
+// A meta column of the node table, as produced by the row function.
+type SQLColumn struct {
+	Name  string
+	Type  string
+	Value any
+}
+
+type SQLColumnDef struct {
+	Name string
+	Type string
+}
+
+// A table and its rows.  Row values line up with Columns.
+type SQLTable struct {
+	Name       string
+	Columns    []SQLColumnDef
+	PrimaryKey []string
+	Rows       [][]any
+}
+
+// A row keyed by column name.
+type SQLRow map[string]any
+
+func (t SQLTable) row(values []any) SQLRow {
+	result := make(SQLRow, len(t.Columns))
+	for i, column := range t.Columns {
+		result[column.Name] = values[i]
+	}
+	return result
+}
+
+// The tables exported for a graph.
+type SQLExport struct {
+	Layout SQLLayout
+	Tables []SQLTable
+}
+
+type SQLOptions[M any] struct {
+	// The name of the node table; other tables are named after it, e.g. "nodes_edges".  Defaults to "nodes".
+	Table string
+	// Map meta to the columns of its row in the node table.  Every row must have the same columns.
+	Row func(meta M) ([]SQLColumn, error)
+}
+
+func (o SQLOptions[M]) table(suffix string) string {
+	name := o.Table
+	if name == "" {
+		name = "nodes"
+	}
+	return name + suffix
+}
+
+// Get the row data for a graph or tree in the provided layout.
+func ExportSQL[T MetaNode[T, M], M any](root T, layout SQLLayout, options SQLOptions[M]) (SQLExport, error) {
+	var nodes []T
+	WalkOnce[T](root, VisitorFuncs[T]{
+		OnEnter: func(node T, depth int) VisitAction {
+			nodes = append(nodes, node)
+			return Continue
+		},
+	})
+
+	nodeTable := SQLTable{
+		Name:       options.table(""),
+		Columns:    []SQLColumnDef{{Name: "id", Type: "TEXT"}},
+		PrimaryKey: []string{"id"},
+	}
+	var structure map[any][]any
+	var err error
+	switch layout {
+	case NestedSets:
+		nodeTable.Columns = append(nodeTable.Columns, SQLColumnDef{Name: "lft", Type: "INTEGER"}, SQLColumnDef{Name: "rgt", Type: "INTEGER"}, SQLColumnDef{Name: "depth", Type: "INTEGER"})
+		structure, err = nestedSets(root)
+	case MaterializedPath:
+		nodeTable.Columns = append(nodeTable.Columns, SQLColumnDef{Name: "path", Type: "TEXT"}, SQLColumnDef{Name: "depth", Type: "INTEGER"}, SQLColumnDef{Name: "position", Type: "INTEGER"})
+		structure, err = materializedPaths(root)
+	}
+	if err != nil {
+		return SQLExport{}, err
+	}
+
+	structuralColumns := len(nodeTable.Columns)
+	for i, node := range nodes {
+		row := append([]any{node.GetID()}, structure[node]...)
+		if options.Row != nil {
+			columns, err := options.Row(node.GetMeta())
+			if err != nil {
+				return SQLExport{}, fmt.Errorf("node %q: %w", node.GetID(), err)
+			}
+			if i == 0 {
+				for _, column := range columns {
+					nodeTable.Columns = append(nodeTable.Columns, SQLColumnDef{Name: column.Name, Type: column.Type})
+				}
+			}
+			if len(columns) != len(nodeTable.Columns)-structuralColumns {
+				return SQLExport{}, fmt.Errorf("node %q has %d columns, expected %d", node.GetID(), len(columns), len(nodeTable.Columns)-structuralColumns)
+			}
+			for j, column := range columns {
+				if column.Name != nodeTable.Columns[structuralColumns+j].Name {
+					return SQLExport{}, fmt.Errorf("node %q has column %q, expected %q", node.GetID(), column.Name, nodeTable.Columns[structuralColumns+j].Name)
+				}
+				row = append(row, column.Value)
+			}
+		}
+		nodeTable.Rows = append(nodeTable.Rows, row)
+	}
+
+	result := SQLExport{
+		Layout: layout,
+		Tables: []SQLTable{nodeTable},
+	}
+	switch layout {
+	case AdjacencyList:
+		result.Tables = append(result.Tables, edgeTable(nodes, options.table("_edges")))
+	case ClosureTable:
+		result.Tables = append(result.Tables, closureTable(nodes, options.table("_closure")))
+	}
+	return result, nil
+}
+
+func edgeTable[T Node[T]](nodes []T, name string) SQLTable {
+	table := SQLTable{
+		Name: name,
+		Columns: []SQLColumnDef{
+			{Name: "parent_id", Type: "TEXT"},
+			{Name: "child_id", Type: "TEXT"},
+			{Name: "position", Type: "INTEGER"},
+		},
+		PrimaryKey: []string{"parent_id", "child_id"},
+	}
+	for _, node := range nodes {
+		for i, child := range node.GetChildren() {
+			table.Rows = append(table.Rows, []any{node.GetID(), child.GetID(), i})
+		}
+	}
+	return table
+}
+
+func closureTable[T Node[T]](nodes []T, name string) SQLTable {
+	table := SQLTable{
+		Name: name,
+		Columns: []SQLColumnDef{
+			{Name: "ancestor_id", Type: "TEXT"},
+			{Name: "descendant_id", Type: "TEXT"},
+			{Name: "depth", Type: "INTEGER"},
+		},
+		PrimaryKey: []string{"ancestor_id", "descendant_id"},
+	}
+	for _, node := range nodes {
+		// Breadth-first, so each descendant is paired at its shortest depth, in child order.
+		seen := map[any]bool{node: true}
+		level := []T{node}
+		for depth := 0; len(level) > 0; depth++ {
+			var next []T
+			for _, descendant := range level {
+				table.Rows = append(table.Rows, []any{node.GetID(), descendant.GetID(), depth})
+				for _, child := range descendant.GetChildren() {
+					if !seen[child] {
+						seen[child] = true
+						next = append(next, child)
+					}
+				}
+			}
+			level = next
+		}
+	}
+	return table
+}
+
+func nestedSets[T Node[T]](root T) (map[any][]any, error) {
+	result := make(map[any][]any)
+	counter := 0
+	var visit func(T, int) error
+	visit = func(node T, depth int) error {
+		if _, exists := result[node]; exists {
+			return fmt.Errorf("%w: %q", ErrMultipleParents, node.GetID())
+		}
+		counter++
+		left := counter
+		result[node] = nil
+		for _, child := range node.GetChildren() {
+			if err := visit(child, depth+1); err != nil {
+				return err
+			}
+		}
+		counter++
+		result[node] = []any{left, counter, depth}
+		return nil
+	}
+	return result, visit(root, 0)
+}
+
+func materializedPaths[T Node[T]](root T) (map[any][]any, error) {
+	result := make(map[any][]any)
+	var visit func(T, NodePath, int) error
+	visit = func(node T, path NodePath, position int) error {
+		if _, exists := result[node]; exists {
+			return fmt.Errorf("%w: %q", ErrMultipleParents, node.GetID())
+		}
+		path = append(path[:len(path):len(path)], node.GetID())
+		result[node] = []any{path.String(), len(path) - 1, position}
+		for i, child := range node.GetChildren() {
+			if err := visit(child, path, i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return result, visit(root, nil, 0)
+}
+
+// Get the statements that create the tables.
+func (e SQLExport) DDL() string {
+	var b strings.Builder
+	for _, table := range e.Tables {
+		var columns []string
+		for _, column := range table.Columns {
+			definition := quoteSQLIdentifier(column.Name) + " " + column.Type
+			if len(table.PrimaryKey) == 1 && table.PrimaryKey[0] == column.Name {
+				definition += " PRIMARY KEY"
+			}
+			columns = append(columns, definition)
+		}
+		if len(table.PrimaryKey) > 1 {
+			var keys []string
+			for _, key := range table.PrimaryKey {
+				keys = append(keys, quoteSQLIdentifier(key))
+			}
+			columns = append(columns, "PRIMARY KEY ("+strings.Join(keys, ", ")+")")
+		}
+		fmt.Fprintf(&b, "CREATE TABLE %s (\n  %s\n);\n", quoteSQLIdentifier(table.Name), strings.Join(columns, ",\n  "))
+	}
+	return b.String()
+}
+
+// Get the statements that insert the rows.
+func (e SQLExport) Inserts() string {
+	var b strings.Builder
+	for _, table := range e.Tables {
+		var columns []string
+		for _, column := range table.Columns {
+			columns = append(columns, quoteSQLIdentifier(column.Name))
+		}
+		for _, row := range table.Rows {
+			values := make([]string, len(row))
+			for i, value := range row {
+				values[i] = sqlLiteral(value)
+			}
+			fmt.Fprintf(&b, "INSERT INTO %s (%s) VALUES (%s);\n", quoteSQLIdentifier(table.Name), strings.Join(columns, ", "), strings.Join(values, ", "))
+		}
+	}
+	return b.String()
+}
+
+func (e SQLExport) String() string {
+	return e.DDL() + e.Inserts()
+}
+
+func quoteSQLIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func sqlLiteral(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v)
+	case float32:
+		if !isFinite(float64(v)) {
+			return "NULL"
+		}
+		return fmt.Sprint(v)
+	case float64:
+		// SQL has no literals for NaN or infinity.
+		if !isFinite(v) {
+			return "NULL"
+		}
+		return fmt.Sprint(v)
+	case []byte:
+		return "X'" + hex.EncodeToString(v) + "'"
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	default:
+		return sqlLiteral(fmt.Sprint(v))
+	}
+}
+
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// Rebuild a graph from exported row data, such as rows read back from the database.  The meta function is called
+// with each row of the node table.  Nodes are shared between their parents.
+func GraphFromSQL[M any](export SQLExport, meta func(row SQLRow) (M, error)) (Graph[M], error) {
+	nodes, order, err := sqlNodes(export, meta, func(id string, m M) Graph[M] {
+		return &graph[M]{ID: id, Meta: m, Children: []Graph[M]{}, parents: []Graph[M]{}}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return linkSQLNodes(export, nodes, order)
+}
+
+// Rebuild a tree from exported row data.  The meta function is called with each row of the node table.
+func TreeFromSQL[M any](export SQLExport, meta func(row SQLRow) (M, error)) (Tree[M], error) {
+	nodes, order, err := sqlNodes(export, meta, func(id string, m M) Tree[M] {
+		return &TreeNode[M]{ID: id, Meta: m, Children: []Tree[M]{}}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return linkSQLNodes(export, nodes, order)
+}
+
+func sqlNodes[T Node[T], M any](export SQLExport, meta func(SQLRow) (M, error), makeNode func(string, M) T) (map[string]T, []sqlNodeRow, error) {
+	if len(export.Tables) == 0 {
+		return nil, nil, fmt.Errorf("%w: no node table", ErrSQLImport)
+	}
+	table := export.Tables[0]
+	nodes := make(map[string]T, len(table.Rows))
+	var rows []sqlNodeRow
+	for i, values := range table.Rows {
+		row := sqlRowReader{table: table.Name, index: i, row: table.row(values)}
+		id, err := row.text("id")
+		if err != nil {
+			return nil, nil, err
+		}
+		m, err := meta(row.row)
+		if err != nil {
+			return nil, nil, fmt.Errorf("node %q: %w", id, err)
+		}
+		nodes[id] = makeNode(id, m)
+		rows = append(rows, sqlNodeRow{id: id, row: row})
+	}
+	return nodes, rows, nil
+}
+
+// A row of the node table, in table order.
+type sqlNodeRow struct {
+	id  string
+	row sqlRowReader
+}
+
+type sqlEdge struct {
+	parent   string
+	child    string
+	position int
+}
+
+func linkSQLNodes[T Node[T]](export SQLExport, nodes map[string]T, rows []sqlNodeRow) (T, error) {
+	var zero T
+	var edges []sqlEdge
+	switch export.Layout {
+	case AdjacencyList, ClosureTable:
+		if len(export.Tables) < 2 {
+			return zero, fmt.Errorf("%w: missing edge table", ErrSQLImport)
+		}
+		table := export.Tables[1]
+		for i, values := range table.Rows {
+			row := sqlRowReader{table: table.Name, index: i, row: table.row(values)}
+			var edge sqlEdge
+			var err error
+			if export.Layout == AdjacencyList {
+				edge, err = row.edge("parent_id", "child_id")
+				if err == nil {
+					edge.position, err = row.optionalInteger("position", i)
+				}
+			} else {
+				var depth int
+				if depth, err = row.integer("depth"); err == nil && depth != 1 {
+					continue
+				}
+				if err == nil {
+					edge, err = row.edge("ancestor_id", "descendant_id")
+					edge.position = i
+				}
+			}
+			if err != nil {
+				return zero, err
+			}
+			edges = append(edges, edge)
+		}
+	case NestedSets:
+		var err error
+		edges, err = nestedSetEdges(rows)
+		if err != nil {
+			return zero, err
+		}
+	case MaterializedPath:
+		for i, node := range rows {
+			path, err := node.row.text("path")
+			if err != nil {
+				return zero, err
+			}
+			ids := strings.Split(strings.TrimPrefix(path, "/"), "/")
+			if len(ids) < 2 {
+				continue
+			}
+			position, err := node.row.optionalInteger("position", i)
+			if err != nil {
+				return zero, err
+			}
+			edges = append(edges, sqlEdge{parent: ids[len(ids)-2], child: ids[len(ids)-1], position: position})
+		}
+	}
+
+	sort.SliceStable(edges, func(i, j int) bool {
+		return edges[i].position < edges[j].position
+	})
+	hasParent := make(map[string]bool)
+	for _, edge := range edges {
+		parent, ok := nodes[edge.parent]
+		child, childOK := nodes[edge.child]
+		if !ok || !childOK {
+			return zero, fmt.Errorf("%w: edge %q -> %q references a missing node", ErrSQLImport, edge.parent, edge.child)
+		}
+		parent.AddChild(child)
+		hasParent[edge.child] = true
+	}
+
+	// The root is the first node without a parent.
+	for _, node := range rows {
+		if !hasParent[node.id] {
+			return nodes[node.id], nil
+		}
+	}
+	return zero, fmt.Errorf("%w: no root node", ErrSQLImport)
+}
+
+func nestedSetEdges(rows []sqlNodeRow) ([]sqlEdge, error) {
+	type bounds struct {
+		id    string
+		left  int
+		right int
+	}
+	var nodes []bounds
+	for _, node := range rows {
+		left, err := node.row.integer("lft")
+		if err != nil {
+			return nil, err
+		}
+		right, err := node.row.integer("rgt")
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, bounds{node.id, left, right})
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].left < nodes[j].left
+	})
+
+	// Walk the nodes in order, keeping a stack of the subtrees that enclose the current node.
+	var edges []sqlEdge
+	var stack []bounds
+	for _, node := range nodes {
+		for len(stack) > 0 && stack[len(stack)-1].right < node.left {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) > 0 {
+			edges = append(edges, sqlEdge{parent: stack[len(stack)-1].id, child: node.id, position: node.left})
+		}
+		stack = append(stack, node)
+	}
+	return edges, nil
+}
+
+// Reads the columns of a row scanned from a database or written by ExportSQL, reporting the table, row and column of
+// values that can't be read.
+type sqlRowReader struct {
+	table string
+	index int
+	row   SQLRow
+}
+
+func (r sqlRowReader) invalid(column, expected string) error {
+	value := r.row[column]
+	if value == nil {
+		return fmt.Errorf("%w: row %d of %s: column %s is NULL, not %s", ErrSQLImport, r.index, r.table, column, expected)
+	}
+	return fmt.Errorf("%w: row %d of %s: column %s is %T %v, not %s", ErrSQLImport, r.index, r.table, column, value, value, expected)
+}
+
+// Read a text column.  Drivers such as MySQL's return text as []byte.
+func (r sqlRowReader) text(column string) (string, error) {
+	switch v := r.row[column].(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case sql.RawBytes:
+		return string(v), nil
+	default:
+		return "", r.invalid(column, "text")
+	}
+}
+
+func (r sqlRowReader) edge(parentColumn, childColumn string) (sqlEdge, error) {
+	parent, err := r.text(parentColumn)
+	if err != nil {
+		return sqlEdge{}, err
+	}
+	child, err := r.text(childColumn)
+	if err != nil {
+		return sqlEdge{}, err
+	}
+	return sqlEdge{parent: parent, child: child}, nil
+}
+
+// Read an integer column of any integer type, a float without a fraction, or text holding an integer.
+func (r sqlRowReader) integer(column string) (int, error) {
+	if text, err := r.text(column); err == nil {
+		if n, err := strconv.Atoi(strings.TrimSpace(text)); err == nil {
+			return n, nil
+		}
+		return 0, r.invalid(column, "an integer")
+	}
+	v := reflect.ValueOf(r.row[column])
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); isFinite(f) && f == math.Trunc(f) {
+			return int(f), nil
+		}
+	}
+	return 0, r.invalid(column, "an integer")
+}
+
+// Read an integer column that may be missing or NULL.
+func (r sqlRowReader) optionalInteger(column string, fallback int) (int, error) {
+	if r.row[column] == nil {
+		return fallback, nil
+	}
+	return r.integer(column)
+}
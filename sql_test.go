@@ -0,0 +1,188 @@
+package girraph
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var customGraphSQL = SQLOptions[CustomGraph]{
+	Row: func(meta CustomGraph) ([]SQLColumn, error) {
+		return []SQLColumn{{Name: "name", Type: "TEXT", Value: meta.GetName()}}, nil
+	},
+}
+
+func customGraphFromRow(row SQLRow) (CustomGraph, error) {
+	return &customGraph{Name: row["name"].(string)}, nil
+}
+
+var customTreeSQL = SQLOptions[CustomTree]{
+	Table: "tree",
+	Row: func(meta CustomTree) ([]SQLColumn, error) {
+		return []SQLColumn{{Name: "name", Type: "TEXT", Value: meta.GetName()}}, nil
+	},
+}
+
+func customTreeFromRow(row SQLRow) (CustomTree, error) {
+	return &customTree{Name: row["name"].(string)}, nil
+}
+
+func TestExportSQL_AdjacencyList(t *testing.T) {
+	export, err := ExportSQL(getGraphFixture(), AdjacencyList, customGraphSQL)
+	require.Nil(t, err)
+
+	assert.Equal(t, `CREATE TABLE "nodes" (
+  "id" TEXT PRIMARY KEY,
+  "name" TEXT
+);
+CREATE TABLE "nodes_edges" (
+  "parent_id" TEXT,
+  "child_id" TEXT,
+  "position" INTEGER,
+  PRIMARY KEY ("parent_id", "child_id")
+);
+`, export.DDL())
+	assert.Equal(t, `INSERT INTO "nodes" ("id", "name") VALUES ('A', 'node A');
+INSERT INTO "nodes" ("id", "name") VALUES ('B', 'node B');
+INSERT INTO "nodes" ("id", "name") VALUES ('D', 'node D');
+INSERT INTO "nodes" ("id", "name") VALUES ('C', 'node C');
+INSERT INTO "nodes_edges" ("parent_id", "child_id", "position") VALUES ('A', 'B', 0);
+INSERT INTO "nodes_edges" ("parent_id", "child_id", "position") VALUES ('A', 'C', 1);
+INSERT INTO "nodes_edges" ("parent_id", "child_id", "position") VALUES ('B', 'D', 0);
+INSERT INTO "nodes_edges" ("parent_id", "child_id", "position") VALUES ('C', 'D', 0);
+`, export.Inserts())
+
+	graph, err := GraphFromSQL(export, customGraphFromRow)
+	require.Nil(t, err)
+	assertGraphFixture(t, graph)
+}
+
+func TestExportSQL_ClosureTable(t *testing.T) {
+	export, err := ExportSQL(getGraphFixture(), ClosureTable, customGraphSQL)
+	require.Nil(t, err)
+
+	require.Len(t, export.Tables, 2)
+	assert.Equal(t, "nodes_closure", export.Tables[1].Name)
+	assert.Equal(t, [][]any{
+		{"A", "A", 0}, {"A", "B", 1}, {"A", "C", 1}, {"A", "D", 2},
+		{"B", "B", 0}, {"B", "D", 1},
+		{"D", "D", 0},
+		{"C", "C", 0}, {"C", "D", 1},
+	}, export.Tables[1].Rows)
+
+	graph, err := GraphFromSQL(export, customGraphFromRow)
+	require.Nil(t, err)
+	assertGraphFixture(t, graph)
+}
+
+func TestExportSQL_NestedSets(t *testing.T) {
+	export, err := ExportSQL(getTreeFixture(), NestedSets, customTreeSQL)
+	require.Nil(t, err)
+
+	require.Len(t, export.Tables, 1)
+	assert.Equal(t, [][]any{
+		{"A", 1, 8, 0, "node A"},
+		{"B", 2, 3, 1, "node B"},
+		{"C", 4, 7, 1, "node C"},
+		{"D", 5, 6, 2, "node D"},
+	}, export.Tables[0].Rows)
+
+	tree, err := TreeFromSQL(export, customTreeFromRow)
+	require.Nil(t, err)
+	assertTreeFixture(t, tree)
+}
+
+func TestExportSQL_MaterializedPath(t *testing.T) {
+	export, err := ExportSQL(getTreeFixture(), MaterializedPath, customTreeSQL)
+	require.Nil(t, err)
+
+	assert.Equal(t, [][]any{
+		{"A", "/A", 0, 0, "node A"},
+		{"B", "/A/B", 1, 0, "node B"},
+		{"C", "/A/C", 1, 1, "node C"},
+		{"D", "/A/C/D", 2, 0, "node D"},
+	}, export.Tables[0].Rows)
+
+	tree, err := TreeFromSQL(export, customTreeFromRow)
+	require.Nil(t, err)
+	assertTreeFixture(t, tree)
+}
+
+func TestExportSQL_MultipleParents(t *testing.T) {
+	_, err := ExportSQL(getGraphFixture(), NestedSets, customGraphSQL)
+	assert.True(t, errors.Is(err, ErrMultipleParents))
+}
+
+func TestSQLLiteral(t *testing.T) {
+	assert.Equal(t, "NULL", sqlLiteral(nil))
+	assert.Equal(t, "'it''s'", sqlLiteral("it's"))
+	assert.Equal(t, "TRUE", sqlLiteral(true))
+	assert.Equal(t, "1.5", sqlLiteral(1.5))
+	assert.Equal(t, "X'0aff'", sqlLiteral([]byte{10, 255}))
+	assert.Equal(t, "NULL", sqlLiteral(math.NaN()))
+	assert.Equal(t, "NULL", sqlLiteral(math.Inf(-1)))
+	assert.Equal(t, "NULL", sqlLiteral(float32(math.Inf(1))))
+	assert.Equal(t, "0.1", sqlLiteral(float32(0.1)))
+}
+
+// Convert the values of every row the way a driver that returns text as []byte and integers as int8 would.
+func asDriverRows(export SQLExport) SQLExport {
+	for _, table := range export.Tables {
+		for _, row := range table.Rows {
+			for i, value := range row {
+				switch v := value.(type) {
+				case string:
+					row[i] = []byte(v)
+				case int:
+					row[i] = int8(v)
+				}
+			}
+		}
+	}
+	return export
+}
+
+func customTreeFromDriverRow(row SQLRow) (CustomTree, error) {
+	return &customTree{Name: string(row["name"].([]byte))}, nil
+}
+
+func TestTreeFromSQL_DriverTypes(t *testing.T) {
+	for _, layout := range []SQLLayout{AdjacencyList, ClosureTable, NestedSets, MaterializedPath} {
+		export, err := ExportSQL(getTreeFixture(), layout, customTreeSQL)
+		require.Nil(t, err)
+
+		tree, err := TreeFromSQL(asDriverRows(export), customTreeFromDriverRow)
+		require.Nil(t, err)
+		assertTreeFixture(t, tree)
+	}
+}
+
+func TestTreeFromSQL_InvalidValue(t *testing.T) {
+	export, err := ExportSQL(getTreeFixture(), MaterializedPath, customTreeSQL)
+	require.Nil(t, err)
+	export.Tables[0].Rows[2][1] = 42
+
+	_, err = TreeFromSQL(export, customTreeFromRow)
+	require.True(t, errors.Is(err, ErrSQLImport))
+	assert.Equal(t, "invalid SQL rows: row 2 of tree: column path is int 42, not text", err.Error())
+
+	export.Tables[0].Rows[2][0] = nil
+	_, err = TreeFromSQL(export, customTreeFromRow)
+	assert.Equal(t, "invalid SQL rows: row 2 of tree: column id is NULL, not text", err.Error())
+}
+
+func assertGraphFixture(t *testing.T, graph Graph[CustomGraph]) {
+	assert.Empty(t, Validate(graph))
+	assert.Equal(t, []string{"A", "B", "D", "C", "D"}, getIDs(graph))
+	assert.Equal(t, "node D", FindNodesByID(graph, "D")[0].GetMeta().GetName())
+	assert.Len(t, FindNodesByID(graph, "D")[0].GetParents(), 2)
+}
+
+func assertTreeFixture(t *testing.T, tree Tree[CustomTree]) {
+	assert.Empty(t, Validate(tree))
+	assert.Equal(t, []string{"A", "B", "C", "D"}, getIDs(tree))
+	assert.Equal(t, "node D", FindNodesByID(tree, "D")[0].GetMeta().GetName())
+}
@@ -0,0 +1,194 @@
+package girraph
+
+import (
+	"math"
+	"sort"
+)
+
+// The number of nodes reported in GraphStats.TopInDegree.
+const statsTopNodes = 10
+
+// A summary of the shape of the graph below a root.  Degrees only count edges between nodes below the root.
+type GraphStats struct {
+	// The number of distinct nodes.
+	Nodes int
+	// The number of times nodes appear when the graph is expanded into a tree, i.e. once per path from the root.
+	// Edges back to a node on the path are not followed.  The count stops at math.MaxInt, which chains of shared
+	// nodes can reach.
+	References int
+	// References divided by Nodes; 1 for a tree.
+	SharingFactor float64
+	// The number of nodes with more than one parent.
+	SharedNodes int
+	Edges       int
+	// The number of nodes without children.
+	Leaves int
+	// The number of nodes without parents that are connected to the root, following both child and parent links.
+	// Unlike the other counts, this includes nodes that are not below the root.
+	Roots int
+
+	MaxFanOut int
+	// The average number of children of the nodes that have children.
+	AvgFanOut float64
+	MaxFanIn  int
+	// The average number of parents of the nodes that have parents.
+	AvgFanIn float64
+
+	// The length of the longest shortest path from the root to a node.
+	MaxDepth int
+	// The number of nodes at each depth, by the shortest path from the root.
+	DepthDistribution []int
+	// The nodes with the most parents, most first, then by ID.  Nodes with fewer than two parents are left out.
+	TopInDegree []NodeDegree
+}
+
+type NodeDegree struct {
+	ID     string
+	Degree int
+}
+
+// Get a summary of the graph or tree below root.
+func Stats[T Node[T]](root T) GraphStats {
+	var nodes []T
+	type edge struct {
+		parent, child any
+	}
+	backEdges := make(map[edge]bool)
+	inDegree := make(map[any]int)
+	references := make(map[any]int)
+	onPath := make(map[any]bool)
+	visited := make(map[any]bool)
+	// Nodes in post-order, so that in reverse each node comes before its children, ignoring back edges.
+	var order []T
+	var visit func(node T)
+	visit = func(node T) {
+		visited[node] = true
+		onPath[node] = true
+		nodes = append(nodes, node)
+		for _, child := range node.GetChildren() {
+			inDegree[child]++
+			if onPath[child] {
+				backEdges[edge{node, child}] = true
+				continue
+			}
+			if !visited[child] {
+				visit(child)
+			}
+		}
+		delete(onPath, node)
+		order = append(order, node)
+	}
+	visit(root)
+
+	result := GraphStats{
+		Nodes: len(nodes),
+	}
+
+	references[root] = 1
+	for i := len(order) - 1; i >= 0; i-- {
+		node := order[i]
+		result.References = addSaturated(result.References, references[node])
+		for _, child := range node.GetChildren() {
+			if !backEdges[edge{node, child}] {
+				references[child] = addSaturated(references[child], references[node])
+			}
+		}
+	}
+	result.SharingFactor = float64(result.References) / float64(result.Nodes)
+
+	var withChildren, withParents int
+	for _, node := range nodes {
+		fanOut := len(node.GetChildren())
+		fanIn := inDegree[node]
+		result.Edges += fanOut
+		if fanOut == 0 {
+			result.Leaves++
+		} else {
+			withChildren++
+		}
+		if fanIn > 0 {
+			withParents++
+		}
+		if fanIn > 1 {
+			result.SharedNodes++
+			result.TopInDegree = append(result.TopInDegree, NodeDegree{
+				ID:     node.GetID(),
+				Degree: fanIn,
+			})
+		}
+		if fanOut > result.MaxFanOut {
+			result.MaxFanOut = fanOut
+		}
+		if fanIn > result.MaxFanIn {
+			result.MaxFanIn = fanIn
+		}
+	}
+	result.Roots = countRoots(root)
+	if withChildren > 0 {
+		result.AvgFanOut = float64(result.Edges) / float64(withChildren)
+	}
+	if withParents > 0 {
+		result.AvgFanIn = float64(result.Edges) / float64(withParents)
+	}
+
+	sort.SliceStable(result.TopInDegree, func(i, j int) bool {
+		a, b := result.TopInDegree[i], result.TopInDegree[j]
+		if a.Degree != b.Degree {
+			return a.Degree > b.Degree
+		}
+		return a.ID < b.ID
+	})
+	if len(result.TopInDegree) > statsTopNodes {
+		result.TopInDegree = result.TopInDegree[:statsTopNodes]
+	}
+
+	depths := map[any]int{root: 0}
+	queue := []T{root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		depth := depths[node]
+		for len(result.DepthDistribution) <= depth {
+			result.DepthDistribution = append(result.DepthDistribution, 0)
+		}
+		result.DepthDistribution[depth]++
+		for _, child := range node.GetChildren() {
+			if _, ok := depths[child]; !ok {
+				depths[child] = depth + 1
+				queue = append(queue, child)
+			}
+		}
+	}
+	result.MaxDepth = len(result.DepthDistribution) - 1
+	return result
+}
+
+// Add two non-negative counts, stopping at math.MaxInt instead of overflowing.
+func addSaturated(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+// Count the nodes without parents in the weakly connected component of start.
+func countRoots[T Node[T]](start T) int {
+	visited := map[any]bool{start: true}
+	queue := []T{start}
+	roots := 0
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		parents := node.GetParents()
+		if len(parents) == 0 {
+			roots++
+		}
+		for _, neighbour := range append(append([]T{}, node.GetChildren()...), parents...) {
+			if !visited[neighbour] {
+				visited[neighbour] = true
+				queue = append(queue, neighbour)
+			}
+		}
+	}
+	return roots
+}
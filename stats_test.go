@@ -0,0 +1,99 @@
+package girraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats_Graph(t *testing.T) {
+	result := Stats(getGraphFixture())
+	assert.Equal(t, GraphStats{
+		Nodes:             4,
+		References:        5,
+		SharingFactor:     1.25,
+		SharedNodes:       1,
+		Edges:             4,
+		Leaves:            1,
+		Roots:             1,
+		MaxFanOut:         2,
+		AvgFanOut:         4.0 / 3,
+		MaxFanIn:          2,
+		AvgFanIn:          4.0 / 3,
+		MaxDepth:          2,
+		DepthDistribution: []int{1, 2, 1},
+		TopInDegree:       []NodeDegree{{ID: "D", Degree: 2}},
+	}, result)
+
+	data, err := json.Marshal(result)
+	require.Nil(t, err)
+	assert.Contains(t, string(data), `"TopInDegree":[{"ID":"D","Degree":2}]`)
+}
+
+func TestStats_Subgraph(t *testing.T) {
+	// Degrees only count edges below B, but roots are found through parent links too.
+	nodeB := getGraphFixture().GetChildren()[0]
+	result := Stats(nodeB)
+	assert.Equal(t, 2, result.Nodes)
+	assert.Equal(t, 1, result.Edges)
+	assert.Equal(t, 1, result.Roots)
+	assert.Equal(t, 1, result.Leaves)
+	assert.Equal(t, 1, result.MaxFanIn)
+}
+
+func TestStats_Roots(t *testing.T) {
+	shared := MakeGraph[CustomGraph]().SetID("S")
+	first := MakeGraph[CustomGraph]().SetID("R1").AddChild(shared)
+	MakeGraph[CustomGraph]().SetID("R2").AddChild(shared)
+	MakeGraph[CustomGraph]().SetID("R3").AddChild(MakeGraph[CustomGraph]().SetID("T"))
+
+	assert.Equal(t, 2, Stats(first).Roots, "R3 is not connected")
+	assert.Equal(t, 2, Stats(shared).Roots)
+}
+
+func TestStats_DiamondChain(t *testing.T) {
+	// Each diamond doubles the number of paths, so 70 of them overflow an int64.
+	root := MakeGraph[CustomGraph]().SetID("0")
+	bottom := root
+	for i := 1; i <= 70; i++ {
+		next := MakeGraph[CustomGraph]().SetID(strconv.Itoa(i))
+		bottom.SetChildren([]Graph[CustomGraph]{
+			MakeGraph[CustomGraph]().SetID(fmt.Sprintf("%d-left", i)).AddChild(next),
+			MakeGraph[CustomGraph]().SetID(fmt.Sprintf("%d-right", i)).AddChild(next),
+		})
+		bottom = next
+	}
+
+	result := Stats(root)
+	assert.Equal(t, 211, result.Nodes)
+	assert.Equal(t, math.MaxInt, result.References)
+	assert.Greater(t, result.SharingFactor, 1.0)
+	assert.Equal(t, 70, result.SharedNodes)
+}
+
+func TestStats_Tree(t *testing.T) {
+	result := Stats(getTreeFixture())
+	assert.Equal(t, 4, result.Nodes)
+	assert.Equal(t, 4, result.References)
+	assert.Equal(t, 1.0, result.SharingFactor)
+	assert.Equal(t, 2, result.Leaves)
+	assert.Equal(t, 1.5, result.AvgFanOut)
+	assert.Equal(t, 1.0, result.AvgFanIn)
+	assert.Equal(t, []int{1, 2, 1}, result.DepthDistribution)
+	assert.Empty(t, result.TopInDegree)
+}
+
+func TestStats_Cycle(t *testing.T) {
+	a, _ := getCyclicGraphFixture()
+	result := Stats(a)
+	assert.Equal(t, 4, result.Nodes)
+	assert.Equal(t, 4, result.References, "back edges are not followed")
+	assert.Equal(t, 5, result.Edges)
+	assert.Equal(t, 1, result.Roots, "X is connected through B")
+	assert.Equal(t, []NodeDegree{{ID: "D", Degree: 2}}, result.TopInDegree, "only edges below the root are counted")
+}
@@ -0,0 +1,233 @@
+package girraph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// Persists graph nodes and edges.  Nodes are stored by ID, so a node shared by several parents is stored once.
+type Store[T any] interface {
+	// Load the graph below the node with the provided ID.
+	Load(rootID string) (Graph[T], error)
+	// Save every node below root, replacing the stored edges of those nodes with their current children.
+	Save(root Graph[T]) error
+
+	GetNode(id string) (NodeRecord[T], error)
+	PutNode(node NodeRecord[T]) error
+	// Delete a node along with the edges to and from it.
+	DeleteNode(id string) error
+
+	// Get the edges from a parent to its children, in order.
+	GetEdges(parentID string) ([]EdgeRecord, error)
+	// Add an edge, or move it to a new index if it exists.
+	PutEdge(edge EdgeRecord) error
+	DeleteEdge(parentID, childID string) error
+}
+
+type NodeRecord[T any] struct {
+	ID   string
+	Meta T
+}
+
+// An edge from a parent to a child.  Children are ordered by Index.
+type EdgeRecord struct {
+	Parent string
+	Child  string
+	Index  int
+}
+
+// A single change to a store.  The file store logs changes in batches of these.
+type storeOp struct {
+	Op     string
+	ID     string          `json:",omitempty"`
+	Meta   json.RawMessage `json:",omitempty"`
+	Parent string          `json:",omitempty"`
+	Child  string          `json:",omitempty"`
+	Index  int             `json:",omitempty"`
+	// The offset of the line abandoned by an opAbandon.
+	Offset int64 `json:",omitempty"`
+}
+
+const (
+	opPutNode    = "put_node"
+	opDeleteNode = "delete_node"
+	opPutEdge    = "put_edge"
+	opDeleteEdge = "delete_edge"
+	// Written by the file store after a partial line left by a writer that crashed, so that the line is skipped.
+	opAbandon = "abandon"
+)
+
+// The contents of a store, with meta kept encoded so that stored values are not shared with callers.
+type storeState struct {
+	nodes map[string]json.RawMessage
+	edges map[string][]EdgeRecord
+}
+
+func makeStoreState() *storeState {
+	return &storeState{
+		nodes: make(map[string]json.RawMessage),
+		edges: make(map[string][]EdgeRecord),
+	}
+}
+
+func (s *storeState) apply(op storeOp) error {
+	switch op.Op {
+	case opPutNode:
+		s.nodes[op.ID] = op.Meta
+	case opDeleteNode:
+		delete(s.nodes, op.ID)
+		delete(s.edges, op.ID)
+		for parentID := range s.edges {
+			s.deleteEdge(parentID, op.ID)
+		}
+	case opPutEdge:
+		s.deleteEdge(op.Parent, op.Child)
+		edges := append(s.edges[op.Parent], EdgeRecord{
+			Parent: op.Parent,
+			Child:  op.Child,
+			Index:  op.Index,
+		})
+		sort.SliceStable(edges, func(i, j int) bool {
+			return edges[i].Index < edges[j].Index
+		})
+		s.edges[op.Parent] = edges
+	case opDeleteEdge:
+		s.deleteEdge(op.Parent, op.Child)
+	default:
+		return fmt.Errorf("unknown store operation %q", op.Op)
+	}
+	return nil
+}
+
+func (s *storeState) deleteEdge(parentID, childID string) {
+	edges := s.edges[parentID]
+	for i, edge := range edges {
+		if edge.Child == childID {
+			edges = append(edges[:i:i], edges[i+1:]...)
+			break
+		}
+	}
+	if len(edges) == 0 {
+		delete(s.edges, parentID)
+	} else {
+		s.edges[parentID] = edges
+	}
+}
+
+func (s *storeState) getEdges(parentID string) []EdgeRecord {
+	return append([]EdgeRecord{}, s.edges[parentID]...)
+}
+
+func getNodeRecord[T any](s *storeState, id string) (NodeRecord[T], error) {
+	record := NodeRecord[T]{
+		ID: id,
+	}
+	meta, ok := s.nodes[id]
+	if !ok {
+		return record, fmt.Errorf("node %q: %w", id, ErrNotFound)
+	}
+	err := json.Unmarshal(meta, &record.Meta)
+	if err != nil {
+		return record, fmt.Errorf("node %q: %w", id, err)
+	}
+	return record, nil
+}
+
+func putNodeOp[T any](node NodeRecord[T]) (storeOp, error) {
+	meta, err := json.Marshal(node.Meta)
+	if err != nil {
+		return storeOp{}, fmt.Errorf("node %q: %w", node.ID, err)
+	}
+	return storeOp{
+		Op:   opPutNode,
+		ID:   node.ID,
+		Meta: meta,
+	}, nil
+}
+
+func putEdgeOp(edge EdgeRecord) storeOp {
+	return storeOp{
+		Op:     opPutEdge,
+		Parent: edge.Parent,
+		Child:  edge.Child,
+		Index:  edge.Index,
+	}
+}
+
+func deleteEdgeOp(parentID, childID string) storeOp {
+	return storeOp{
+		Op:     opDeleteEdge,
+		Parent: parentID,
+		Child:  childID,
+	}
+}
+
+// Get the operations that store every node below root and replace their stored edges with their children.
+func saveOps[T any](s *storeState, root Graph[T]) ([]storeOp, error) {
+	var ops []storeOp
+	var err error
+	WalkOnce[Graph[T]](root, VisitorFuncs[Graph[T]]{
+		OnEnter: func(node Graph[T], depth int) VisitAction {
+			op, opErr := putNodeOp(NodeRecord[T]{
+				ID:   node.GetID(),
+				Meta: node.GetMeta(),
+			})
+			if opErr != nil {
+				err = opErr
+				return Stop
+			}
+			ops = append(ops, op)
+
+			children := make(map[string]bool)
+			for i, child := range node.GetChildren() {
+				children[child.GetID()] = true
+				ops = append(ops, putEdgeOp(EdgeRecord{
+					Parent: node.GetID(),
+					Child:  child.GetID(),
+					Index:  i,
+				}))
+			}
+			for _, edge := range s.edges[node.GetID()] {
+				if !children[edge.Child] {
+					ops = append(ops, deleteEdgeOp(edge.Parent, edge.Child))
+				}
+			}
+			return Continue
+		},
+	})
+	return ops, err
+}
+
+// Build the graph below rootID from the stored records.  Nodes are built once and shared between their parents.
+func loadGraph[T any](s *storeState, rootID string) (Graph[T], error) {
+	return loadNode[T](s, rootID, make(map[string]Graph[T]))
+}
+
+func loadNode[T any](s *storeState, id string, nodes map[string]Graph[T]) (Graph[T], error) {
+	if existing, ok := nodes[id]; ok {
+		return existing, nil
+	}
+	record, err := getNodeRecord[T](s, id)
+	if err != nil {
+		return nil, err
+	}
+	result := &graph[T]{
+		ID:       record.ID,
+		Meta:     record.Meta,
+		Children: []Graph[T]{},
+		parents:  []Graph[T]{},
+	}
+	nodes[id] = result
+	for _, edge := range s.edges[id] {
+		child, err := loadNode[T](s, edge.Child, nodes)
+		if err != nil {
+			return nil, err
+		}
+		result.AddChild(child)
+	}
+	return result, nil
+}
@@ -0,0 +1,308 @@
+package girraph
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// A Store backed by an append-only log file.  Each change is appended to the log with a single write, as a line holding
+// a batch of operations, so Save is applied entirely or not at all, and writers never overwrite each other's changes.
+// A partial line left by a writer that crashed is abandoned by the next append.  Before each call the store reads any
+// lines appended since it last looked, so several stores, including stores in other processes, can share a log.  Compact rewrites the log as a snapshot of its current contents; it must not run while
+// stores in other processes are writing.
+type FileStore[T any] struct {
+	path   string
+	mu     sync.Mutex
+	state  *storeState
+	file   os.FileInfo
+	offset int64
+}
+
+// Open the log at path, creating it if needed, and read its contents.
+func OpenFileStore[T any](path string) (*FileStore[T], error) {
+	f := &FileStore[T]{
+		path:  path,
+		state: makeStoreState(),
+	}
+	if err := f.refresh(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *FileStore[T]) Load(rootID string) (Graph[T], error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.refresh(); err != nil {
+		return nil, err
+	}
+	return loadGraph[T](f.state, rootID)
+}
+
+func (f *FileStore[T]) Save(root Graph[T]) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.refresh(); err != nil {
+		return err
+	}
+	ops, err := saveOps(f.state, root)
+	if err != nil {
+		return err
+	}
+	return f.append(ops)
+}
+
+func (f *FileStore[T]) GetNode(id string) (NodeRecord[T], error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.refresh(); err != nil {
+		return NodeRecord[T]{}, err
+	}
+	return getNodeRecord[T](f.state, id)
+}
+
+func (f *FileStore[T]) PutNode(node NodeRecord[T]) error {
+	op, err := putNodeOp(node)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.append([]storeOp{op})
+}
+
+func (f *FileStore[T]) DeleteNode(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.append([]storeOp{{
+		Op: opDeleteNode,
+		ID: id,
+	}})
+}
+
+func (f *FileStore[T]) GetEdges(parentID string) ([]EdgeRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.refresh(); err != nil {
+		return nil, err
+	}
+	return f.state.getEdges(parentID), nil
+}
+
+func (f *FileStore[T]) PutEdge(edge EdgeRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.append([]storeOp{putEdgeOp(edge)})
+}
+
+func (f *FileStore[T]) DeleteEdge(parentID, childID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.append([]storeOp{deleteEdgeOp(parentID, childID)})
+}
+
+// Rewrite the log as a single batch that recreates its current contents.
+func (f *FileStore[T]) Compact() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.refresh(); err != nil {
+		return err
+	}
+
+	var ids []string
+	for id := range f.state.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var ops []storeOp
+	for _, id := range ids {
+		ops = append(ops, storeOp{
+			Op:   opPutNode,
+			ID:   id,
+			Meta: f.state.nodes[id],
+		})
+	}
+	for _, id := range ids {
+		for _, edge := range f.state.edges[id] {
+			ops = append(ops, putEdgeOp(edge))
+		}
+	}
+	line, err := encodeBatch(ops)
+	if err != nil {
+		return err
+	}
+
+	temp := f.path + ".compact"
+	if err := writeFileSync(temp, line); err != nil {
+		return err
+	}
+	if err := os.Rename(temp, f.path); err != nil {
+		return err
+	}
+
+	// Start over from the compacted log.
+	f.state = makeStoreState()
+	f.file = nil
+	f.offset = 0
+	return f.refresh()
+}
+
+func writeFileSync(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func encodeBatch(ops []storeOp) ([]byte, error) {
+	line, err := json.Marshal(ops)
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
+
+// Append a batch to the log, then read it back along with anything else appended since the last refresh.  If the log
+// ends in a partial line, left behind by a writer that crashed, the line is ended and abandoned before the batch, so
+// that the batch isn't joined to it.
+func (f *FileStore[T]) append(ops []storeOp) error {
+	line, err := encodeBatch(ops)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	partial, err := partialLineStart(file)
+	if err == nil {
+		if partial >= 0 {
+			var abandon []byte
+			if abandon, err = encodeBatch([]storeOp{{Op: opAbandon, Offset: partial}}); err == nil {
+				line = append(append([]byte{'\n'}, abandon...), line...)
+			}
+		}
+		if err == nil {
+			_, err = file.Write(line)
+		}
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return f.refresh()
+}
+
+// Get the offset of the partial line at the end of the log, or -1 if the log ends in a newline.
+func partialLineStart(file *os.File) (int64, error) {
+	info, err := file.Stat()
+	if err != nil || info.Size() == 0 {
+		return -1, err
+	}
+	size := info.Size()
+	buf := make([]byte, 4096)
+	for end := size; end > 0; {
+		start := end - int64(len(buf))
+		if start < 0 {
+			start = 0
+		}
+		chunk := buf[:end-start]
+		if _, err := file.ReadAt(chunk, start); err != nil {
+			return -1, err
+		}
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			if start+int64(i)+1 == size {
+				return -1, nil
+			}
+			return start + int64(i) + 1, nil
+		}
+		end = start
+	}
+	return 0, nil
+}
+
+// Apply any complete lines appended to the log since the last refresh.  If the log was replaced, e.g. by Compact in
+// another store, it is read again from the start.
+func (f *FileStore[T]) refresh() error {
+	file, err := os.OpenFile(f.path, os.O_RDONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if f.file != nil && (!os.SameFile(f.file, info) || info.Size() < f.offset) {
+		f.state = makeStoreState()
+		f.offset = 0
+	}
+	f.file = info
+
+	if _, err := file.Seek(f.offset, io.SeekStart); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(file)
+	offset := f.offset
+	// A line that doesn't parse is only skipped if the next line abandons it.
+	abandoned := int64(-1)
+	var abandonedErr error
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// A partial line is a batch that was never finished, and is abandoned by the next append.
+			if abandoned >= 0 {
+				return fmt.Errorf("failed to read %s at offset %d: %w", f.path, abandoned, abandonedErr)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start := offset
+		offset += int64(len(line))
+		line = bytes.TrimSpace(line)
+		var ops []storeOp
+		if len(line) > 0 {
+			if err := json.Unmarshal(line, &ops); err != nil {
+				if abandoned < 0 {
+					abandoned, abandonedErr = start, err
+					continue
+				}
+				return fmt.Errorf("failed to read %s at offset %d: %w", f.path, abandoned, abandonedErr)
+			}
+		}
+		if abandoned >= 0 {
+			if len(ops) != 1 || ops[0].Op != opAbandon || ops[0].Offset != abandoned {
+				return fmt.Errorf("failed to read %s at offset %d: %w", f.path, abandoned, abandonedErr)
+			}
+			abandoned = -1
+		}
+		for _, op := range ops {
+			if op.Op == opAbandon {
+				continue
+			}
+			if err := f.state.apply(op); err != nil {
+				return err
+			}
+		}
+		f.offset = offset
+	}
+}
@@ -0,0 +1,83 @@
+package girraph
+
+import "sync"
+
+// A Store that keeps records in memory.  It is safe for concurrent use.
+type MemoryStore[T any] struct {
+	mu    sync.RWMutex
+	state *storeState
+}
+
+func NewMemoryStore[T any]() *MemoryStore[T] {
+	return &MemoryStore[T]{
+		state: makeStoreState(),
+	}
+}
+
+func (m *MemoryStore[T]) Load(rootID string) (Graph[T], error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return loadGraph[T](m.state, rootID)
+}
+
+func (m *MemoryStore[T]) Save(root Graph[T]) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ops, err := saveOps(m.state, root)
+	if err != nil {
+		return err
+	}
+	return m.apply(ops...)
+}
+
+func (m *MemoryStore[T]) GetNode(id string) (NodeRecord[T], error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return getNodeRecord[T](m.state, id)
+}
+
+func (m *MemoryStore[T]) PutNode(node NodeRecord[T]) error {
+	op, err := putNodeOp(node)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.apply(op)
+}
+
+func (m *MemoryStore[T]) DeleteNode(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.apply(storeOp{
+		Op: opDeleteNode,
+		ID: id,
+	})
+}
+
+func (m *MemoryStore[T]) GetEdges(parentID string) ([]EdgeRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.state.getEdges(parentID), nil
+}
+
+func (m *MemoryStore[T]) PutEdge(edge EdgeRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.apply(putEdgeOp(edge))
+}
+
+func (m *MemoryStore[T]) DeleteEdge(parentID, childID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.apply(deleteEdgeOp(parentID, childID))
+}
+
+func (m *MemoryStore[T]) apply(ops ...storeOp) error {
+	for _, op := range ops {
+		if err := m.state.apply(op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package girraph
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, func(t *testing.T) Store[*customGraph] {
+		return NewMemoryStore[*customGraph]()
+	})
+}
+
+func TestFileStore(t *testing.T) {
+	testStore(t, func(t *testing.T) Store[*customGraph] {
+		store, err := OpenFileStore[*customGraph](filepath.Join(t.TempDir(), "graph.log"))
+		require.Nil(t, err)
+		return store
+	})
+}
+
+func TestFileStore_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.log")
+	store, err := OpenFileStore[*customGraph](path)
+	require.Nil(t, err)
+	require.Nil(t, store.Save(getStoreFixture()))
+
+	reopened, err := OpenFileStore[*customGraph](path)
+	require.Nil(t, err)
+	assertStoreFixture(t, reopened)
+}
+
+func TestFileStore_SharedLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.log")
+	first, err := OpenFileStore[*customGraph](path)
+	require.Nil(t, err)
+	second, err := OpenFileStore[*customGraph](path)
+	require.Nil(t, err)
+
+	require.Nil(t, first.Save(getStoreFixture()))
+	require.Nil(t, second.PutNode(NodeRecord[*customGraph]{ID: "E", Meta: &customGraph{Name: "node E"}}))
+	require.Nil(t, second.PutEdge(EdgeRecord{Parent: "C", Child: "E", Index: 1}))
+
+	edges, err := first.GetEdges("C")
+	require.Nil(t, err)
+	assert.Equal(t, []EdgeRecord{{Parent: "C", Child: "D"}, {Parent: "C", Child: "E", Index: 1}}, edges)
+}
+
+func TestFileStore_Compact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.log")
+	store, err := OpenFileStore[*customGraph](path)
+	require.Nil(t, err)
+	other, err := OpenFileStore[*customGraph](path)
+	require.Nil(t, err)
+
+	for i := 0; i < 3; i++ {
+		require.Nil(t, store.Save(getStoreFixture()))
+	}
+	before, err := os.Stat(path)
+	require.Nil(t, err)
+
+	require.Nil(t, store.Compact())
+	after, err := os.Stat(path)
+	require.Nil(t, err)
+	assert.Less(t, after.Size(), before.Size())
+	assertStoreFixture(t, store)
+
+	// Other stores notice the log was replaced and read it again.
+	require.Nil(t, store.DeleteEdge("A", "B"))
+	edges, err := other.GetEdges("A")
+	require.Nil(t, err)
+	assert.Equal(t, []EdgeRecord{{Parent: "A", Child: "C", Index: 1}}, edges)
+}
+
+func TestFileStore_PartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.log")
+	store, err := OpenFileStore[*customGraph](path)
+	require.Nil(t, err)
+	require.Nil(t, store.Save(getStoreFixture()))
+
+	// A writer crashed halfway through a batch.
+	appendToFile(t, path, `[{"Op":"put_node","ID":"X","Me`)
+
+	reopened, err := OpenFileStore[*customGraph](path)
+	require.Nil(t, err)
+	assertStoreFixture(t, reopened)
+
+	// The next batch starts on a new line, and the unfinished one is skipped.
+	require.Nil(t, store.PutNode(NodeRecord[*customGraph]{ID: "E", Meta: &customGraph{Name: "node E"}}))
+	_, err = store.GetNode("X")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	reopened, err = OpenFileStore[*customGraph](path)
+	require.Nil(t, err)
+	assertStoreFixture(t, reopened)
+	node, err := reopened.GetNode("E")
+	require.Nil(t, err)
+	assert.Equal(t, "node E", node.Meta.Name)
+
+	// Another crash after the first is abandoned as well.
+	appendToFile(t, path, `[{"Op":"put_`)
+	require.Nil(t, reopened.DeleteNode("E"))
+	reopened, err = OpenFileStore[*customGraph](path)
+	require.Nil(t, err)
+	assertStoreFixture(t, reopened)
+
+	require.Nil(t, reopened.Compact())
+	assertStoreFixture(t, reopened)
+}
+
+func TestFileStore_CorruptLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.log")
+	store, err := OpenFileStore[*customGraph](path)
+	require.Nil(t, err)
+	require.Nil(t, store.Save(getStoreFixture()))
+	info, err := os.Stat(path)
+	require.Nil(t, err)
+
+	// A complete line that doesn't parse wasn't left by a crash, so it isn't skipped.
+	appendToFile(t, path, "not a batch\n")
+	offset := fmt.Sprintf("at offset %d", info.Size())
+	_, err = store.GetNode("A")
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), offset)
+
+	_, err = OpenFileStore[*customGraph](path)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), offset)
+}
+
+func appendToFile(t *testing.T, path, data string) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	require.Nil(t, err)
+	_, err = file.WriteString(data)
+	require.Nil(t, err)
+	require.Nil(t, file.Close())
+}
+
+// Run the tests every Store implementation must pass.
+func testStore(t *testing.T, makeStore func(t *testing.T) Store[*customGraph]) {
+	t.Run("SaveLoad", func(t *testing.T) {
+		store := makeStore(t)
+		require.Nil(t, store.Save(getStoreFixture()))
+		assertStoreFixture(t, store)
+	})
+
+	t.Run("SaveReplacesEdges", func(t *testing.T) {
+		store := makeStore(t)
+		graph := getStoreFixture()
+		require.Nil(t, store.Save(graph))
+
+		graph.SetChildren([]Graph[*customGraph]{graph.GetChildren()[1]})
+		require.Nil(t, store.Save(graph))
+
+		edges, err := store.GetEdges("A")
+		require.Nil(t, err)
+		assert.Equal(t, []EdgeRecord{{Parent: "A", Child: "C"}}, edges)
+
+		// Removed children are kept until they are deleted.
+		_, err = store.GetNode("B")
+		assert.Nil(t, err)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		store := makeStore(t)
+		_, err := store.Load("A")
+		assert.True(t, errors.Is(err, ErrNotFound))
+		_, err = store.GetNode("A")
+		assert.True(t, errors.Is(err, ErrNotFound))
+
+		edges, err := store.GetEdges("A")
+		require.Nil(t, err)
+		assert.Empty(t, edges)
+	})
+
+	t.Run("Nodes", func(t *testing.T) {
+		store := makeStore(t)
+		require.Nil(t, store.PutNode(NodeRecord[*customGraph]{ID: "A", Meta: &customGraph{Name: "first"}}))
+		require.Nil(t, store.PutNode(NodeRecord[*customGraph]{ID: "A", Meta: &customGraph{Name: "second"}}))
+
+		node, err := store.GetNode("A")
+		require.Nil(t, err)
+		assert.Equal(t, "second", node.Meta.Name)
+
+		require.Nil(t, store.DeleteNode("A"))
+		_, err = store.GetNode("A")
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("Edges", func(t *testing.T) {
+		store := makeStore(t)
+		require.Nil(t, store.PutEdge(EdgeRecord{Parent: "A", Child: "B", Index: 1}))
+		require.Nil(t, store.PutEdge(EdgeRecord{Parent: "A", Child: "C", Index: 0}))
+		require.Nil(t, store.PutEdge(EdgeRecord{Parent: "A", Child: "D", Index: 2}))
+
+		edges, err := store.GetEdges("A")
+		require.Nil(t, err)
+		assert.Equal(t, []EdgeRecord{
+			{Parent: "A", Child: "C", Index: 0},
+			{Parent: "A", Child: "B", Index: 1},
+			{Parent: "A", Child: "D", Index: 2},
+		}, edges)
+
+		// Moving an edge doesn't duplicate it.
+		require.Nil(t, store.PutEdge(EdgeRecord{Parent: "A", Child: "B", Index: 3}))
+		require.Nil(t, store.DeleteEdge("A", "C"))
+		edges, err = store.GetEdges("A")
+		require.Nil(t, err)
+		assert.Equal(t, []EdgeRecord{
+			{Parent: "A", Child: "D", Index: 2},
+			{Parent: "A", Child: "B", Index: 3},
+		}, edges)
+	})
+
+	t.Run("DeleteNodeDeletesEdges", func(t *testing.T) {
+		store := makeStore(t)
+		require.Nil(t, store.Save(getStoreFixture()))
+		require.Nil(t, store.DeleteNode("D"))
+
+		graph, err := store.Load("A")
+		require.Nil(t, err)
+		assert.Equal(t, []string{"A", "B", "C"}, getIDs(graph))
+	})
+
+	t.Run("StoredValuesAreCopies", func(t *testing.T) {
+		store := makeStore(t)
+		graph := getStoreFixture()
+		require.Nil(t, store.Save(graph))
+		graph.GetMeta().Name = "changed"
+
+		node, err := store.GetNode("A")
+		require.Nil(t, err)
+		assert.Equal(t, "node A", node.Meta.Name)
+	})
+}
+
+func getStoreFixture() Graph[*customGraph] {
+	nodeD := MakeGraph[*customGraph]().SetID("D").SetMeta(&customGraph{Name: "node D"})
+	return MakeGraph[*customGraph]().SetID("A").SetMeta(&customGraph{Name: "node A"}).SetChildren([]Graph[*customGraph]{
+		MakeGraph[*customGraph]().SetID("B").SetMeta(&customGraph{Name: "node B"}).AddChild(nodeD),
+		MakeGraph[*customGraph]().SetID("C").SetMeta(&customGraph{Name: "node C"}).AddChild(nodeD),
+	})
+}
+
+func assertStoreFixture(t *testing.T, store Store[*customGraph]) {
+	graph, err := store.Load("A")
+	require.Nil(t, err)
+	assert.Empty(t, Validate(graph))
+	assert.Equal(t, []string{"A", "B", "D", "C", "D"}, getIDs(graph))
+	assert.Equal(t, "node D", FindNodesByID(graph, "D")[0].GetMeta().GetName())
+	assert.Len(t, FindNodesByID(graph, "D")[0].GetParents(), 2)
+}
@@ -0,0 +1,223 @@
+package girraph
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrTransactionDone = errors.New("transaction has already been committed or rolled back")
+
+// Stages changes to the nodes below a root so that they are applied together or not at all.  Nothing is changed until
+// Commit; the staged values can be read back with GetID, GetMeta and GetChildren.  A transaction is not safe for
+// concurrent use, and the nodes must not be changed directly while it is open.
+type Transaction[T MetaNode[T, M], M any] struct {
+	root   T
+	staged map[any]*stagedNode[T, M]
+	order  []T
+	done   bool
+}
+
+type stagedNode[T any, M any] struct {
+	id          string
+	hasID       bool
+	meta        M
+	hasMeta     bool
+	children    []T
+	hasChildren bool
+}
+
+// Begin a transaction on the nodes below root.
+func Begin[T MetaNode[T, M], M any](root T) *Transaction[T, M] {
+	return &Transaction[T, M]{
+		root:   root,
+		staged: make(map[any]*stagedNode[T, M]),
+	}
+}
+
+// Run fn in a transaction, committing it if fn returns nil and rolling it back if fn returns an error or panics.
+func Update[T MetaNode[T, M], M any](root T, fn func(tx *Transaction[T, M]) error) error {
+	tx := Begin[T, M](root)
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (tx *Transaction[T, M]) stage(node T) *stagedNode[T, M] {
+	s, ok := tx.staged[node]
+	if !ok {
+		s = &stagedNode[T, M]{}
+		tx.staged[node] = s
+		tx.order = append(tx.order, node)
+	}
+	return s
+}
+
+func (tx *Transaction[T, M]) SetID(node T, id string) {
+	s := tx.stage(node)
+	s.id = id
+	s.hasID = true
+}
+
+func (tx *Transaction[T, M]) GetID(node T) string {
+	if s, ok := tx.staged[node]; ok && s.hasID {
+		return s.id
+	}
+	return node.GetID()
+}
+
+func (tx *Transaction[T, M]) SetMeta(node T, meta M) {
+	s := tx.stage(node)
+	s.meta = meta
+	s.hasMeta = true
+}
+
+func (tx *Transaction[T, M]) GetMeta(node T) M {
+	if s, ok := tx.staged[node]; ok && s.hasMeta {
+		return s.meta
+	}
+	return node.GetMeta()
+}
+
+func (tx *Transaction[T, M]) SetChildren(parent T, children []T) {
+	s := tx.stage(parent)
+	s.children = append([]T{}, children...)
+	s.hasChildren = true
+}
+
+func (tx *Transaction[T, M]) GetChildren(node T) []T {
+	if s, ok := tx.staged[node]; ok && s.hasChildren {
+		return append([]T{}, s.children...)
+	}
+	return append([]T{}, node.GetChildren()...)
+}
+
+func (tx *Transaction[T, M]) AddChild(parent, child T) {
+	tx.SetChildren(parent, append(tx.GetChildren(parent), child))
+}
+
+// Remove child from the children of parent.
+func (tx *Transaction[T, M]) RemoveChild(parent, child T) {
+	tx.SetChildren(parent, removeNode(tx.GetChildren(parent), child))
+}
+
+// Discard the staged changes.
+func (tx *Transaction[T, M]) Rollback() {
+	tx.staged = make(map[any]*stagedNode[T, M])
+	tx.order = nil
+	tx.done = true
+}
+
+// Check the staged changes and apply them.  If they would create a cycle, or give a tree node more than one parent,
+// nothing is changed and an error is returned.  Observers of root receive the events of the commit in a single batch.
+func (tx *Transaction[T, M]) Commit() error {
+	if tx.done {
+		return ErrTransactionDone
+	}
+	if err := tx.check(); err != nil {
+		return err
+	}
+	tx.done = true
+	Batch(tx.root, tx.apply)
+	return nil
+}
+
+// Apply the staged changes, restoring the previous values if a node panics part way through.
+func (tx *Transaction[T, M]) apply() {
+	var undo []func()
+	defer func() {
+		if r := recover(); r != nil {
+			for i := len(undo) - 1; i >= 0; i-- {
+				undo[i]()
+			}
+			panic(r)
+		}
+	}()
+	for _, node := range tx.order {
+		node := node
+		s := tx.staged[node]
+		if s.hasID {
+			old := node.GetID()
+			undo = append(undo, func() { node.SetID(old) })
+			node.SetID(s.id)
+		}
+		if s.hasMeta {
+			old := node.GetMeta()
+			undo = append(undo, func() { node.SetMeta(old) })
+			node.SetMeta(s.meta)
+		}
+		if s.hasChildren {
+			old := append([]T{}, node.GetChildren()...)
+			undo = append(undo, func() { node.SetChildren(old) })
+			node.SetChildren(s.children)
+		}
+	}
+	tx.staged = nil
+	tx.order = nil
+}
+
+func (tx *Transaction[T, M]) check() error {
+	// Tree nodes have a single parent, so a child may only be staged under one of them.
+	parents := make(map[any][]T)
+	for _, node := range tx.order {
+		if s := tx.staged[node]; s.hasChildren {
+			for _, child := range s.children {
+				parents[child] = append(parents[child], node)
+			}
+		}
+	}
+	for _, node := range tx.order {
+		s := tx.staged[node]
+		if !s.hasChildren {
+			continue
+		}
+		for _, child := range s.children {
+			if _, ok := any(child).(interface{ GetParent() T }); !ok {
+				continue
+			}
+			count := len(parents[child])
+			for _, parent := range child.GetParents() {
+				if staged, ok := tx.staged[parent]; !ok || !staged.hasChildren {
+					count++
+				}
+			}
+			if count > 1 {
+				return fmt.Errorf("%w: %q", ErrMultipleParents, tx.GetID(child))
+			}
+		}
+	}
+
+	onPath := make(map[any]bool)
+	visited := make(map[any]bool)
+	var visit func(node T) error
+	visit = func(node T) error {
+		if onPath[node] {
+			return fmt.Errorf("%w: node %q is its own descendant", ErrCycle, tx.GetID(node))
+		}
+		if visited[node] {
+			return nil
+		}
+		visited[node] = true
+		onPath[node] = true
+		defer delete(onPath, node)
+		for _, child := range tx.GetChildren(node) {
+			if err := visit(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, node := range append([]T{tx.root}, tx.order...) {
+		if err := visit(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package girraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction_Commit(t *testing.T) {
+	root := getGraphFixture()
+	calls, _ := recordEvents(t, root)
+	b := FindNodesByID(root, "B")[0]
+	c := FindNodesByID(root, "C")[0]
+	d := FindNodesByID(root, "D")[0]
+	e := MakeGraph[CustomGraph]().SetID("E")
+
+	tx := Begin(root)
+	tx.RemoveChild(c, d)
+	tx.AddChild(c, e)
+	tx.SetMeta(e, &customGraph{Name: "node E"})
+	tx.SetID(b, "B2")
+
+	assert.Equal(t, []Graph[CustomGraph]{e}, tx.GetChildren(c))
+	assert.Equal(t, "B2", tx.GetID(b))
+	assert.Equal(t, []string{"A", "B", "D", "C", "D"}, getIDs(root), "nothing changes before commit")
+	assert.Empty(t, *calls)
+
+	require.Nil(t, tx.Commit())
+	assert.Equal(t, []string{"A", "B2", "D", "C", "E"}, getIDs(root))
+	assert.Equal(t, "node E", e.GetMeta().GetName())
+	assert.Equal(t, []Graph[CustomGraph]{b}, d.GetParents())
+	assert.Equal(t, []Graph[CustomGraph]{c}, e.GetParents())
+	assert.Empty(t, Validate(root))
+	assert.Len(t, *calls, 1, "the commit is delivered as one batch")
+
+	assert.True(t, errors.Is(tx.Commit(), ErrTransactionDone))
+}
+
+func TestTransaction_Rollback(t *testing.T) {
+	root := getGraphFixture()
+	b := FindNodesByID(root, "B")[0]
+
+	tx := Begin(root)
+	tx.SetChildren(b, []Graph[CustomGraph]{})
+	tx.SetMeta(b, &customGraph{Name: "changed"})
+	tx.Rollback()
+
+	assert.Equal(t, []string{"A", "B", "D", "C", "D"}, getIDs(root))
+	assert.Equal(t, "node B", b.GetMeta().GetName())
+	assert.True(t, errors.Is(tx.Commit(), ErrTransactionDone))
+}
+
+func TestTransaction_Cycle(t *testing.T) {
+	root := getGraphFixture()
+	d := FindNodesByID(root, "D")[0]
+
+	tx := Begin(root)
+	tx.SetMeta(d, &customGraph{Name: "changed"})
+	tx.AddChild(d, root)
+	assert.True(t, errors.Is(tx.Commit(), ErrCycle))
+	assert.Equal(t, "node D", d.GetMeta().GetName(), "nothing is applied when the commit fails")
+	assert.Empty(t, Validate(root))
+}
+
+func TestTransaction_TreeMultipleParents(t *testing.T) {
+	root := getTreeFixture()
+	b := root.GetChildren()[0]
+	c := root.GetChildren()[1]
+	d := c.GetChildren()[0]
+
+	tx := Begin(root)
+	tx.AddChild(b, d)
+	assert.True(t, errors.Is(tx.Commit(), ErrMultipleParents))
+
+	// Moving the node is fine.
+	tx = Begin(root)
+	tx.AddChild(b, d)
+	tx.RemoveChild(c, d)
+	require.Nil(t, tx.Commit())
+	assert.Equal(t, []string{"A", "B", "D", "C"}, getIDs(root))
+	assert.Equal(t, b, d.GetParent())
+	assert.Empty(t, Validate(root))
+}
+
+func TestUpdate(t *testing.T) {
+	root := getTreeFixture()
+	failed := errors.New("failed")
+
+	err := Update(root, func(tx *Transaction[Tree[CustomTree], CustomTree]) error {
+		tx.SetChildren(root, []Tree[CustomTree]{})
+		return failed
+	})
+	assert.Equal(t, failed, err)
+	assert.Equal(t, []string{"A", "B", "C", "D"}, getIDs(root))
+
+	err = Update(root, func(tx *Transaction[Tree[CustomTree], CustomTree]) error {
+		tx.SetChildren(root, tx.GetChildren(root)[1:])
+		return nil
+	})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"A", "C", "D"}, getIDs(root))
+}
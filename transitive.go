@@ -0,0 +1,208 @@
+package girraph
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Which nodes below a root can be reached from each other through child edges.  A node only reaches itself if it is
+// part of a cycle.
+type Reachability[T any] struct {
+	nodes []Graph[T]
+	index map[Graph[T]]int
+	reach []bitset
+}
+
+// Get the transitive closure of the graph below root.  The graph is not changed, and may contain cycles.
+func TransitiveClosure[T any](root Graph[T]) *Reachability[T] {
+	r := &Reachability[T]{
+		index: make(map[Graph[T]]int),
+	}
+	WalkOnce[Graph[T]](root, VisitorFuncs[Graph[T]]{
+		OnEnter: func(node Graph[T], depth int) VisitAction {
+			r.index[node] = len(r.nodes)
+			r.nodes = append(r.nodes, node)
+			return Continue
+		},
+	})
+	r.reach = make([]bitset, len(r.nodes))
+
+	// The components come after the components they reach, so the nodes reached from each child are known by the time
+	// the child's parents are looked at.
+	s := &tarjan[T]{
+		index:   make(map[Graph[T]]int),
+		low:     make(map[Graph[T]]int),
+		onStack: make(map[Graph[T]]bool),
+	}
+	s.connect(root)
+	for _, component := range s.components {
+		reach := makeBitset(len(r.nodes))
+		for _, node := range component {
+			for _, child := range node.GetChildren() {
+				reach.set(r.index[child])
+				reach.union(r.reach[r.index[child]])
+			}
+		}
+		for _, node := range component {
+			r.reach[r.index[node]] = reach
+		}
+	}
+	return r
+}
+
+// Check whether to can be reached from from through one or more child edges.
+func (r *Reachability[T]) Reaches(from, to Graph[T]) bool {
+	i, ok := r.index[from]
+	if !ok {
+		return false
+	}
+	j, ok := r.index[to]
+	return ok && r.reach[i].has(j)
+}
+
+// Get the nodes that can be reached from node, in depth-first order from the root.
+func (r *Reachability[T]) Descendants(node Graph[T]) []Graph[T] {
+	i, ok := r.index[node]
+	if !ok {
+		return nil
+	}
+	var result []Graph[T]
+	for _, j := range r.reach[i].members() {
+		result = append(result, r.nodes[j])
+	}
+	return result
+}
+
+// Get the nodes that node can be reached from, in depth-first order from the root.
+func (r *Reachability[T]) Ancestors(node Graph[T]) []Graph[T] {
+	j, ok := r.index[node]
+	if !ok {
+		return nil
+	}
+	var result []Graph[T]
+	for i, reach := range r.reach {
+		if reach.has(j) {
+			result = append(result, r.nodes[i])
+		}
+	}
+	return result
+}
+
+// Get every pair of a node and a node it reaches, ordered by the first node and then the second in depth-first order.
+func (r *Reachability[T]) Pairs() []EdgeKey {
+	var result []EdgeKey
+	for i, reach := range r.reach {
+		for _, j := range reach.members() {
+			result = append(result, EdgeKey{
+				Parent: r.nodes[i].GetID(),
+				Child:  r.nodes[j].GetID(),
+			})
+		}
+	}
+	return result
+}
+
+// Copy the graph below root without its redundant edges: edges to a child that can also be reached through another
+// child, and repeated edges.  The copy has the same reachability as the original with the fewest edges.  The graph
+// must not contain cycles, since their reduction is not unique.
+func TransitiveReduction[T any](root Graph[T]) (Graph[T], error) {
+	_, kept, err := reducedChildren(root)
+	if err != nil {
+		return nil, err
+	}
+	copies := make(map[Graph[T]]Graph[T])
+	var copyNode func(node Graph[T]) (Graph[T], error)
+	copyNode = func(node Graph[T]) (Graph[T], error) {
+		if existing, ok := copies[node]; ok {
+			return existing, nil
+		}
+		result, err := shallowCopy(node)
+		if err != nil {
+			return nil, err
+		}
+		copies[node] = result
+		for _, child := range kept[node] {
+			copied, err := copyNode(child)
+			if err != nil {
+				return nil, err
+			}
+			result.AddChild(copied)
+		}
+		return result, nil
+	}
+	return copyNode(root)
+}
+
+// Remove the redundant edges below root, like TransitiveReduction, by changing the nodes.  Observers of root receive
+// the changes in a single batch.
+func TransitiveReductionInPlace[T any](root Graph[T]) error {
+	nodes, kept, err := reducedChildren(root)
+	if err != nil {
+		return err
+	}
+	Batch(root, func() {
+		for _, node := range nodes {
+			if children := kept[node]; len(children) != len(node.GetChildren()) {
+				node.SetChildren(children)
+			}
+		}
+	})
+	return nil
+}
+
+// Get the nodes below root in depth-first order, and the children each of them keeps in the transitive reduction.
+func reducedChildren[T any](root Graph[T]) ([]Graph[T], map[Graph[T]][]Graph[T], error) {
+	r := TransitiveClosure(root)
+	for i, node := range r.nodes {
+		if r.reach[i].has(i) {
+			return nil, nil, fmt.Errorf("%w: node %q is its own descendant", ErrCycle, node.GetID())
+		}
+	}
+	result := make(map[Graph[T]][]Graph[T])
+	for _, node := range r.nodes {
+		indirect := makeBitset(len(r.nodes))
+		for _, child := range node.GetChildren() {
+			indirect.union(r.reach[r.index[child]])
+		}
+		children := []Graph[T]{}
+		for _, child := range node.GetChildren() {
+			if !indirect.has(r.index[child]) && !containsNode(children, child) {
+				children = append(children, child)
+			}
+		}
+		result[node] = children
+	}
+	return r.nodes, result, nil
+}
+
+type bitset []uint64
+
+func makeBitset(size int) bitset {
+	return make(bitset, (size+63)/64)
+}
+
+func (b bitset) set(i int) {
+	b[i/64] |= 1 << (i % 64)
+}
+
+func (b bitset) has(i int) bool {
+	return b[i/64]&(1<<(i%64)) != 0
+}
+
+func (b bitset) union(other bitset) {
+	for i := range other {
+		b[i] |= other[i]
+	}
+}
+
+func (b bitset) members() []int {
+	var result []int
+	for i, word := range b {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			result = append(result, i*64+bit)
+			word &^= 1 << bit
+		}
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package girraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A -> B -> C -> D, with the redundant edges A -> C, A -> D and B -> D, and a repeated edge B -> C.
+func getRedundantGraphFixture() Graph[string] {
+	a := MakeGraph[string]().SetID("A")
+	b := MakeGraph[string]().SetID("B")
+	c := MakeGraph[string]().SetID("C")
+	d := MakeGraph[string]().SetID("D")
+	c.AddChild(d)
+	b.AddChild(c).AddChild(d).AddChild(c)
+	return a.AddChild(c).AddChild(b).AddChild(d)
+}
+
+func nodeIDs[T Node[T]](nodes []T) []string {
+	var result []string
+	for _, node := range nodes {
+		result = append(result, node.GetID())
+	}
+	return result
+}
+
+func TestTransitiveClosure(t *testing.T) {
+	root := getGraphFixture()
+	closure := TransitiveClosure(root)
+	b := FindNodesByID(root, "B")[0]
+	d := FindNodesByID(root, "D")[0]
+
+	assert.True(t, closure.Reaches(root, d))
+	assert.False(t, closure.Reaches(d, b))
+	assert.False(t, closure.Reaches(root, root))
+	assert.Equal(t, []string{"B", "D", "C"}, nodeIDs(closure.Descendants(root)))
+	assert.Equal(t, []string{"A", "B", "C"}, nodeIDs(closure.Ancestors(d)))
+	assert.Equal(t, []EdgeKey{
+		{Parent: "A", Child: "B"}, {Parent: "A", Child: "D"}, {Parent: "A", Child: "C"},
+		{Parent: "B", Child: "D"},
+		{Parent: "C", Child: "D"},
+	}, closure.Pairs())
+
+	a, _ := getCyclicGraphFixture()
+	cyclic := TransitiveClosure(a)
+	assert.True(t, cyclic.Reaches(a, a), "nodes in a cycle reach themselves")
+	assert.Equal(t, []string{"A", "B", "C", "D"}, nodeIDs(cyclic.Descendants(a)))
+	assert.Equal(t, []string{"D"}, nodeIDs(cyclic.Descendants(a.GetChildren()[0].GetChildren()[0].GetChildren()[1])))
+}
+
+func TestTransitiveReduction(t *testing.T) {
+	root := getRedundantGraphFixture()
+	reduced, err := TransitiveReduction(root)
+	require.Nil(t, err)
+
+	assert.Equal(t, []string{"A", "B", "C", "D"}, getIDs(reduced))
+	assert.Equal(t, []string{"A", "C", "D", "B", "C", "D", "D", "C", "D", "D"}, getIDs(root), "the original is not changed")
+	assert.Empty(t, Validate(reduced))
+
+	shared, err := TransitiveReduction(getGraphFixture())
+	require.Nil(t, err)
+	assert.Equal(t, []string{"A", "B", "D", "C", "D"}, getIDs(shared))
+	assert.Len(t, FindNodesByID(shared, "D")[0].GetParents(), 2)
+}
+
+func TestTransitiveReductionInPlace(t *testing.T) {
+	root := getRedundantGraphFixture()
+	calls, _ := recordEvents(t, root)
+
+	require.Nil(t, TransitiveReductionInPlace(root))
+	assert.Equal(t, []string{"A", "B", "C", "D"}, getIDs(root))
+	assert.Empty(t, Validate(root))
+	assert.Len(t, *calls, 1)
+}
+
+func TestTransitiveReduction_Cycle(t *testing.T) {
+	a, _ := getCyclicGraphFixture()
+	_, err := TransitiveReduction(a)
+	assert.True(t, errors.Is(err, ErrCycle))
+	assert.True(t, errors.Is(TransitiveReductionInPlace(a), ErrCycle))
+}
@@ -1,10 +1,6 @@
 package girraph
 
-import (
-	"encoding/json"
-
-	"github.com/google/uuid"
-)
+import "encoding/json"
 
 type Tree[T any] interface {
 	Node[Tree[T]]
@@ -14,30 +10,41 @@ type Tree[T any] interface {
 	GetMeta() T
 }
 
-func MakeTree[T any]() Tree[T] {
+func MakeTree[T any](options ...Option) Tree[T] {
 	return &TreeNode[T]{
-		ID:       uuid.New().String(),
+		ID:       newID(options),
 		Children: []Tree[T]{},
 		parent:   nil,
 	}
 }
 
 type TreeNode[T any] struct {
-	ID       string
-	Meta     T
-	Children []Tree[T]
-	parent   Tree[T]
+	ID        string
+	Meta      T
+	Children  []Tree[T]
+	parent    Tree[T]
+	cache     nodeCache
+	observers observerList[Tree[T]]
 }
 
-func MakeTreeNode[T any]() *TreeNode[T] {
+func MakeTreeNode[T any](options ...Option) *TreeNode[T] {
 	return &TreeNode[T]{
-		ID:       uuid.New().String(),
+		ID:       newID(options),
 		Children: []Tree[T]{},
 	}
 }
 
 func (t *TreeNode[T]) SetID(id string) Tree[T] {
+	old := t.ID
 	t.ID = id
+	if old != id {
+		emit[Tree[T]](t, Event[Tree[T]]{
+			Type:  IDChanged,
+			Node:  t,
+			OldID: old,
+			NewID: id,
+		})
+	}
 	return t
 }
 
@@ -46,7 +53,19 @@ func (t *TreeNode[_]) GetID() string {
 }
 
 func (t *TreeNode[T]) SetChildren(children []Tree[T]) Tree[T] {
+	old := t.Children
+	orphans := orphanChildren(children)
+	for _, child := range t.Children {
+		if any(child.GetParent()) == any(Tree[T](t)) {
+			child.SetParent(nil)
+		}
+	}
+	for _, child := range children {
+		child.SetParent(t)
+	}
 	t.Children = children
+	t.invalidate()
+	emit[Tree[T]](t, childrenEvents[Tree[T]](t, old, children, orphans)...)
 	return t
 }
 
@@ -55,7 +74,11 @@ func (t *TreeNode[T]) GetChildren() []Tree[T] {
 }
 
 func (t *TreeNode[T]) AddChild(child Tree[T]) Tree[T] {
+	orphan := child.GetParent() == nil
+	child.SetParent(t)
 	t.Children = append(t.Children, child)
+	t.invalidate()
+	emit[Tree[T]](t, addChildEvents[Tree[T]](t, child, len(t.Children)-1, orphan)...)
 	return t
 }
 
@@ -70,6 +93,9 @@ func (t *TreeNode[T]) SetParent(parent Tree[T]) Tree[T] {
 }
 
 func (t *TreeNode[T]) GetParents() []Tree[T] {
+	if t.parent == nil {
+		return []Tree[T]{}
+	}
 	return []Tree[T]{t.parent}
 }
 
@@ -82,10 +108,39 @@ func (t *TreeNode[_]) JSON() ([]byte, error) {
 }
 
 func (t *TreeNode[T]) SetMeta(meta T) Tree[T] {
+	old := t.Meta
 	t.Meta = meta
+	t.invalidate()
+	emit[Tree[T]](t, Event[Tree[T]]{
+		Type:    MetaChanged,
+		Node:    t,
+		OldMeta: old,
+		NewMeta: meta,
+	})
 	return t
 }
 
+func (t *TreeNode[T]) shallowCopy() Tree[T] {
+	return &TreeNode[T]{
+		ID:       t.ID,
+		Meta:     t.Meta,
+		Children: []Tree[T]{},
+	}
+}
+
+func (t *TreeNode[T]) getCache() *nodeCache {
+	return &t.cache
+}
+
+func (t *TreeNode[T]) getObservers() *observerList[Tree[T]] {
+	return &t.observers
+}
+
+func (t *TreeNode[T]) invalidate() {
+	t.cache = nodeCache{}
+	invalidateParents(t.GetParents())
+}
+
 func (t *TreeNode[T]) GetMeta() T {
 	return t.Meta
 }
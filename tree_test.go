@@ -56,6 +56,40 @@ func TestTree_JSON(t *testing.T) {
 	assert.Equal(t, expected, result)
 }
 
+func TestTree_AddChild_SetsParent(t *testing.T) {
+	nodeA := MakeTree[CustomTree]().SetID("A")
+	nodeB := MakeTree[CustomTree]().SetID("B")
+
+	nodeA.AddChild(nodeB)
+
+	assert.Same(t, nodeA, nodeB.GetParent())
+	assert.Equal(t, []Tree[CustomTree]{nodeA}, nodeB.GetParents())
+	assert.Empty(t, nodeA.GetParents())
+}
+
+func TestTree_SetChildren_SetsParents(t *testing.T) {
+	tree := getTreeFixture()
+	nodeB := tree.GetChildren()[0]
+	nodeE := MakeTree[CustomTree]().SetID("E")
+
+	tree.SetChildren([]Tree[CustomTree]{nodeE})
+
+	assert.Nil(t, nodeB.GetParent())
+	assert.Same(t, tree, nodeE.GetParent())
+}
+
+func TestTreeFromJSON_SetsParents(t *testing.T) {
+	input, err := getTreeFixture().JSON()
+	require.Nil(t, err)
+
+	tree, err := TreeFromJSON[*customTree](input)
+	require.Nil(t, err)
+
+	nodeC := tree.GetChildren()[1]
+	assert.Same(t, tree, nodeC.GetParent())
+	assert.Same(t, nodeC, nodeC.GetChildren()[0].GetParent())
+}
+
 func getTreeFixture() Tree[CustomTree] {
 	nodeA := MakeTree[CustomTree]().SetID("A").SetMeta(&customTree{})
 	nodeA.GetMeta().SetName("node A")
@@ -0,0 +1,121 @@
+package girraph
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ProblemType string
+
+const (
+	ProblemEmptyID       ProblemType = "empty_id"
+	ProblemDuplicateID   ProblemType = "duplicate_id"
+	ProblemMissingParent ProblemType = "missing_parent"
+	ProblemMissingChild  ProblemType = "missing_child"
+	ProblemCycle         ProblemType = "cycle"
+	ProblemMeta          ProblemType = "meta"
+)
+
+// A problem found by Validate, tagged with the path to the node that has the problem.
+type Problem struct {
+	Type    ProblemType
+	Path    NodePath
+	Message string
+}
+
+func (p Problem) Error() string {
+	return fmt.Sprintf("%s: %s: %s", p.Path, p.Type, p.Message)
+}
+
+// Meta can implement Validator to add its own checks to Validate.  Each returned error is reported as a ProblemMeta.
+type Validator interface {
+	Validate() []error
+}
+
+// Check that the graph below root is well-formed, returning every problem found.  Each node instance is checked once,
+// using the first path it was reached by.
+func Validate[T MetaNode[T, M], M any](root T) []Problem {
+	v := &validation[T, M]{
+		ids:     make(map[string]T),
+		visited: make(map[any]bool),
+		onPath:  make(map[any]bool),
+	}
+	v.visit(root)
+	return v.problems
+}
+
+type validation[T MetaNode[T, M], M any] struct {
+	problems []Problem
+	ids      map[string]T
+	visited  map[any]bool
+	onPath   map[any]bool
+	path     []T
+}
+
+func (v *validation[T, M]) visit(node T) {
+	v.path = append(v.path, node)
+	defer func() {
+		v.path = v.path[:len(v.path)-1]
+	}()
+	v.visited[node] = true
+	v.onPath[node] = true
+	defer delete(v.onPath, node)
+
+	id := node.GetID()
+	if id == "" {
+		v.report(ProblemEmptyID, "node has no id")
+	} else if first, exists := v.ids[id]; exists && any(first) != any(node) {
+		v.report(ProblemDuplicateID, "id is shared by distinct nodes")
+	} else if !exists {
+		v.ids[id] = node
+	}
+
+	for _, parent := range node.GetParents() {
+		if !containsNode(parent.GetChildren(), node) {
+			v.report(ProblemMissingChild, fmt.Sprintf("parent %q does not list the node as a child", parent.GetID()))
+		}
+	}
+
+	if validator, ok := any(node.GetMeta()).(Validator); ok {
+		for _, err := range validator.Validate() {
+			v.report(ProblemMeta, err.Error())
+		}
+	}
+
+	for _, child := range node.GetChildren() {
+		if !containsNode(child.GetParents(), node) {
+			v.report(ProblemMissingParent, fmt.Sprintf("child %q does not list the node as a parent", child.GetID()))
+		}
+		if v.onPath[child] {
+			v.report(ProblemCycle, v.cycle(child))
+			continue
+		}
+		if !v.visited[child] {
+			v.visit(child)
+		}
+	}
+}
+
+func (v *validation[T, M]) report(problemType ProblemType, message string) {
+	path := make(NodePath, len(v.path))
+	for i, node := range v.path {
+		path[i] = node.GetID()
+	}
+	v.problems = append(v.problems, Problem{
+		Type:    problemType,
+		Path:    path,
+		Message: message,
+	})
+}
+
+// Describe the cycle closed by an edge from the current node back to start.
+func (v *validation[T, M]) cycle(start T) string {
+	var ids []string
+	for i := len(v.path) - 1; i >= 0; i-- {
+		ids = append([]string{v.path[i].GetID()}, ids...)
+		if any(v.path[i]) == any(start) {
+			break
+		}
+	}
+	return strings.Join(append(ids, start.GetID()), " -> ")
+}
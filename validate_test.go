@@ -0,0 +1,107 @@
+package girraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validatedMeta struct {
+	Name string
+}
+
+func (m *validatedMeta) Validate() []error {
+	if m.Name == "" {
+		return []error{errors.New("name is required")}
+	}
+	return nil
+}
+
+func TestValidate_Graph(t *testing.T) {
+	assert.Empty(t, Validate(getGraphFixture()))
+}
+
+func TestValidate_Tree(t *testing.T) {
+	assert.Empty(t, Validate(getTreeFixture()))
+}
+
+func TestValidate_GraphFromJSON(t *testing.T) {
+	input, err := getGraphFixture().JSON()
+	require.Nil(t, err)
+
+	graph, err := GraphFromJSON[*customGraph](input)
+	require.Nil(t, err)
+
+	assert.Empty(t, Validate(graph))
+	nodes := FindNodesByID(graph, "D")
+	require.Len(t, nodes, 2)
+	assert.Same(t, nodes[0], nodes[1])
+	assert.Len(t, nodes[0].GetParents(), 2)
+}
+
+func TestValidate_EmptyID(t *testing.T) {
+	graph := MakeGraph[CustomGraph]().SetID("A").AddChild(MakeGraph[CustomGraph]().SetID(""))
+
+	problems := Validate(graph)
+	require.Len(t, problems, 1)
+	assert.Equal(t, ProblemEmptyID, problems[0].Type)
+	assert.Equal(t, NodePath{"A", ""}, problems[0].Path)
+}
+
+func TestValidate_DuplicateID(t *testing.T) {
+	graph := MakeGraph[CustomGraph]().SetID("A").SetChildren([]Graph[CustomGraph]{
+		MakeGraph[CustomGraph]().SetID("B"),
+		MakeGraph[CustomGraph]().SetID("B"),
+	})
+
+	problems := Validate(graph)
+	require.Len(t, problems, 1)
+	assert.Equal(t, ProblemDuplicateID, problems[0].Type)
+	assert.Equal(t, "/A/B", problems[0].Path.String())
+}
+
+func TestValidate_MissingParent(t *testing.T) {
+	graph := getGraphFixture()
+	nodeB := graph.GetChildren()[0]
+	nodeB.SetParents([]Graph[CustomGraph]{})
+
+	problems := Validate(graph)
+	require.Len(t, problems, 1)
+	assert.Equal(t, ProblemMissingParent, problems[0].Type)
+	assert.Equal(t, NodePath{"A"}, problems[0].Path)
+}
+
+func TestValidate_MissingChild(t *testing.T) {
+	graph := getGraphFixture()
+	graph.GetChildren()[0].AddParent(MakeGraph[CustomGraph]().SetID("X"))
+
+	problems := Validate(graph)
+	require.Len(t, problems, 1)
+	assert.Equal(t, ProblemMissingChild, problems[0].Type)
+	assert.Equal(t, NodePath{"A", "B"}, problems[0].Path)
+}
+
+func TestValidate_Cycle(t *testing.T) {
+	graph := getGraphFixture()
+	nodeD := graph.GetChildren()[0].GetChildren()[0]
+	nodeD.AddChild(graph)
+
+	problems := Validate(graph)
+	require.Len(t, problems, 1)
+	assert.Equal(t, ProblemCycle, problems[0].Type)
+	assert.Equal(t, NodePath{"A", "B", "D"}, problems[0].Path)
+	assert.Equal(t, "/A/B/D: cycle: A -> B -> D -> A", problems[0].Error())
+}
+
+func TestValidate_Meta(t *testing.T) {
+	graph := MakeGraph[*validatedMeta]().SetID("A").SetMeta(&validatedMeta{Name: "A"}).AddChild(
+		MakeGraph[*validatedMeta]().SetID("B").SetMeta(&validatedMeta{}),
+	)
+
+	problems := Validate(graph)
+	require.Len(t, problems, 1)
+	assert.Equal(t, ProblemMeta, problems[0].Type)
+	assert.Equal(t, "/A/B: meta: name is required", problems[0].Error())
+}
@@ -0,0 +1,92 @@
+package girraph
+
+// Returned by Visitor.Enter to control the rest of the walk.
+type VisitAction int
+
+const (
+	// Visit the node's children, then exit the node.
+	Continue VisitAction = iota
+	// Exit the node without visiting its children.
+	SkipChildren
+	// End the walk without any further calls to the visitor.
+	Stop
+)
+
+// Visits nodes before and after their children.  Depth is relative to the root of the walk.
+type Visitor[T any] interface {
+	Enter(node T, depth int) VisitAction
+	Exit(node T, depth int)
+}
+
+// Adapts functions to a Visitor.  Either function may be nil.
+type VisitorFuncs[T any] struct {
+	OnEnter func(node T, depth int) VisitAction
+	OnExit  func(node T, depth int)
+}
+
+func (v VisitorFuncs[T]) Enter(node T, depth int) VisitAction {
+	if v.OnEnter == nil {
+		return Continue
+	}
+	return v.OnEnter(node, depth)
+}
+
+func (v VisitorFuncs[T]) Exit(node T, depth int) {
+	if v.OnExit != nil {
+		v.OnExit(node, depth)
+	}
+}
+
+// Walk the nodes below root depth-first, calling Enter before a node's children are walked and Exit after.  Nodes with
+// multiple parents are walked once per parent.  An edge back to a node that is already being walked is not followed.
+func Walk[T Node[T]](root T, visitor Visitor[T]) {
+	w := &walk[T]{
+		visitor: visitor,
+		onPath:  make(map[any]bool),
+	}
+	w.walk(root, 0)
+}
+
+// Walk like Walk, but visit each node only once, from the first parent it is reached through.
+func WalkOnce[T Node[T]](root T, visitor Visitor[T]) {
+	w := &walk[T]{
+		visitor: visitor,
+		onPath:  make(map[any]bool),
+		visited: make(map[any]bool),
+	}
+	w.walk(root, 0)
+}
+
+type walk[T Node[T]] struct {
+	visitor Visitor[T]
+	onPath  map[any]bool
+	visited map[any]bool
+	stopped bool
+}
+
+func (w *walk[T]) walk(node T, depth int) {
+	if w.onPath[node] || w.visited != nil && w.visited[node] {
+		return
+	}
+	if w.visited != nil {
+		w.visited[node] = true
+	}
+
+	switch w.visitor.Enter(node, depth) {
+	case Stop:
+		w.stopped = true
+		return
+	case SkipChildren:
+		break
+	default:
+		w.onPath[node] = true
+		for _, child := range node.GetChildren() {
+			w.walk(child, depth+1)
+			if w.stopped {
+				return
+			}
+		}
+		delete(w.onPath, node)
+	}
+	w.visitor.Exit(node, depth)
+}
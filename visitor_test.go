@@ -0,0 +1,84 @@
+package girraph
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingVisitor[T Node[T]] struct {
+	events []string
+	skip   string
+	stop   string
+}
+
+func (v *recordingVisitor[T]) Enter(node T, depth int) VisitAction {
+	v.events = append(v.events, fmt.Sprintf("enter %s %d", node.GetID(), depth))
+	switch node.GetID() {
+	case v.skip:
+		return SkipChildren
+	case v.stop:
+		return Stop
+	}
+	return Continue
+}
+
+func (v *recordingVisitor[T]) Exit(node T, depth int) {
+	v.events = append(v.events, fmt.Sprintf("exit %s %d", node.GetID(), depth))
+}
+
+func TestWalk_Tree(t *testing.T) {
+	visitor := &recordingVisitor[Tree[CustomTree]]{}
+	Walk[Tree[CustomTree]](getTreeFixture(), visitor)
+	assert.Equal(t, []string{
+		"enter A 0",
+		"enter B 1",
+		"exit B 1",
+		"enter C 1",
+		"enter D 2",
+		"exit D 2",
+		"exit C 1",
+		"exit A 0",
+	}, visitor.events)
+}
+
+func TestWalk_Graph(t *testing.T) {
+	visitor := &recordingVisitor[Graph[CustomGraph]]{skip: "C"}
+	Walk[Graph[CustomGraph]](getGraphFixture(), visitor)
+	assert.Equal(t, []string{
+		"enter A 0",
+		"enter B 1",
+		"enter D 2",
+		"exit D 2",
+		"exit B 1",
+		"enter C 1",
+		"exit C 1",
+		"exit A 0",
+	}, visitor.events)
+}
+
+func TestWalkOnce(t *testing.T) {
+	var ids []string
+	WalkOnce[Graph[CustomGraph]](getGraphFixture(), VisitorFuncs[Graph[CustomGraph]]{
+		OnExit: func(node Graph[CustomGraph], depth int) {
+			ids = append(ids, node.GetID())
+		},
+	})
+	assert.Equal(t, []string{"D", "B", "C", "A"}, ids)
+}
+
+func TestWalk_Stop(t *testing.T) {
+	visitor := &recordingVisitor[Graph[CustomGraph]]{stop: "D"}
+	Walk[Graph[CustomGraph]](getGraphFixture(), visitor)
+	assert.Equal(t, []string{"enter A 0", "enter B 1", "enter D 2"}, visitor.events)
+}
+
+func TestWalk_Cycle(t *testing.T) {
+	graph := getGraphFixture()
+	FindNodesByID(graph, "D")[0].AddChild(graph)
+
+	visitor := &recordingVisitor[Graph[CustomGraph]]{}
+	WalkOnce[Graph[CustomGraph]](graph, visitor)
+	assert.Len(t, visitor.events, 8)
+}